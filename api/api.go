@@ -0,0 +1,388 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// CrawlRequest asks for a crawl of an owner/name repo. Without any analysis, the configured ones run.
+type CrawlRequest struct {
+	Repo       string
+	Forkers    bool `json:",omitempty"`
+	PRs        bool `json:",omitempty"`
+	Stargazers bool `json:",omitempty"`
+}
+
+// CrawlFunc runs the crawl, calling found for every candidate, and returns the candidates
+type CrawlFunc func(ctx context.Context, req CrawlRequest, found func(c *candidate.Candidate)) (*candidate.Set, error)
+
+// The states of a crawl
+const (
+	CrawlQueued  = "queued"
+	CrawlRunning = "running"
+	CrawlDone    = "done"
+	CrawlFailed  = "failed"
+)
+
+// Crawl is a crawl requested through the api, together with its progress
+type Crawl struct {
+	CrawlRequest
+	ID         int
+	State      string
+	Requested  time.Time
+	Started    time.Time `json:",omitempty"`
+	Finished   time.Time `json:",omitempty"`
+	Candidates int
+	Error      string `json:",omitempty"`
+}
+
+// Candidate is a tracked candidate as returned by the api
+type Candidate struct {
+	*candidate.Record
+	Login string
+	Score float64
+}
+
+// Server serves the crawls and the candidate pipeline over a json api.
+// Crawls run one at a time, in the order they were requested, once Run is called.
+type Server struct {
+	// APIKey is expected as a bearer token or in the X-API-Key header
+	APIKey string
+	DB     *candidate.DB
+	// Save persists the candidate pipeline after every change
+	Save  func(db *candidate.DB) error
+	Crawl CrawlFunc
+	// Contactable tells whether a candidate can be shown, filtering out the do-not-contact list
+	Contactable func(c *candidate.Candidate) bool
+	// DoNotContact puts a candidate on the do-not-contact list, nil disabling the route
+	DoNotContact func(c *candidate.Candidate, reason string) error
+	Now          func() time.Time
+
+	mu     sync.Mutex
+	crawls []*Crawl
+	queue  chan *Crawl
+}
+
+// NewServer returns a server crawling and tracking candidates through the given functions
+func NewServer(apiKey string, db *candidate.DB, save func(db *candidate.DB) error, crawl CrawlFunc) *Server {
+	return &Server{
+		APIKey:      apiKey,
+		DB:          db,
+		Save:        save,
+		Crawl:       crawl,
+		Contactable: func(c *candidate.Candidate) bool { return true },
+		Now:         time.Now,
+		queue:       make(chan *Crawl, 100),
+	}
+}
+
+// Run runs the requested crawls until the context is cancelled. It must only be called once:
+// the crawls run one at a time because the CrawlFunc may share state between them, like the
+// crawl commands do with the current run and its fetcher's stats.
+func (s *Server) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case crawl := <-s.queue:
+			s.run(ctx, crawl)
+		}
+	}
+}
+
+func (s *Server) run(ctx context.Context, crawl *Crawl) {
+	s.mu.Lock()
+	crawl.State, crawl.Started = CrawlRunning, s.Now()
+	s.mu.Unlock()
+
+	found, err := s.Crawl(ctx, crawl.CrawlRequest, func(c *candidate.Candidate) {
+		s.mu.Lock()
+		crawl.Candidates++
+		s.mu.Unlock()
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	crawl.Finished = s.Now()
+	if err != nil {
+		crawl.State, crawl.Error = CrawlFailed, err.Error()
+	} else {
+		crawl.State = CrawlDone
+	}
+	if found == nil {
+		return
+	}
+
+	crawl.Candidates = found.Len()
+	for _, c := range found.All() {
+		s.DB.Track(c, crawl.Finished)
+	}
+	if err = s.Save(s.DB); err != nil {
+		log.WithError(err).Error("couldn't save the candidates")
+	}
+}
+
+// Handler returns the api's routes, all of them requiring the api key
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/crawls", s.handleCrawls)
+	mux.HandleFunc("/api/crawls/", s.handleCrawl)
+	mux.HandleFunc("/api/candidates", s.handleCandidates)
+	mux.HandleFunc("/api/candidates/", s.handleCandidate)
+
+	return s.authenticated(mux)
+}
+
+func (s *Server) authenticated(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("X-API-Key")
+		if bearer := r.Header.Get("Authorization"); strings.HasPrefix(bearer, "Bearer ") {
+			key = strings.TrimPrefix(bearer, "Bearer ")
+		}
+		if s.APIKey == "" || subtle.ConstantTimeCompare([]byte(key), []byte(s.APIKey)) != 1 {
+			writeError(w, http.StatusUnauthorized, errors.New("invalid api key"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// handleCrawls lists the crawls on GET and requests one on POST {"repo": "owner/name", "prs": true}
+func (s *Server) handleCrawls(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		writeJSON(w, http.StatusOK, s.crawls)
+
+	case http.MethodPost:
+		var req CrawlRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, errors.Wrap(err, "invalid request"))
+			return
+		}
+		if parts := strings.Split(req.Repo, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			writeError(w, http.StatusBadRequest, errors.Errorf("invalid repo %q, expecting owner/name", req.Repo))
+			return
+		}
+
+		s.mu.Lock()
+		crawl := &Crawl{CrawlRequest: req, ID: len(s.crawls) + 1, State: CrawlQueued, Requested: s.Now()}
+		select {
+		case s.queue <- crawl:
+			s.crawls = append(s.crawls, crawl)
+		default:
+			s.mu.Unlock()
+			writeError(w, http.StatusServiceUnavailable, errors.New("too many crawls queued"))
+			return
+		}
+		copied := *crawl
+		s.mu.Unlock()
+
+		w.Header().Set("Location", fmt.Sprintf("/api/crawls/%d", crawl.ID))
+		writeJSON(w, http.StatusAccepted, copied)
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, errors.Errorf("%s not allowed", r.Method))
+	}
+}
+
+// handleCrawl returns a crawl's progress
+func (s *Server) handleCrawl(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, errors.Errorf("%s not allowed", r.Method))
+		return
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/api/crawls/"))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil || id < 1 || id > len(s.crawls) {
+		writeError(w, http.StatusNotFound, errors.New("no such crawl"))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.crawls[id-1])
+}
+
+// handleCandidates lists the tracked candidates, filtered by the status, tag, repo, role, min_score
+// and q (login, name, location or company) parameters, sorted by score, last_seen or login
+// and limited to limit results
+func (s *Server) handleCandidates(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, errors.Errorf("%s not allowed", r.Method))
+		return
+	}
+
+	query := r.URL.Query()
+	var (
+		minScore float64
+		limit    int
+		err      error
+	)
+	if v := query.Get("min_score"); v != "" {
+		if minScore, err = strconv.ParseFloat(v, 64); err != nil {
+			writeError(w, http.StatusBadRequest, errors.Wrap(err, "invalid min_score"))
+			return
+		}
+	}
+	if v := query.Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil {
+			writeError(w, http.StatusBadRequest, errors.Wrap(err, "invalid limit"))
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.Now()
+	result := []Candidate{}
+	for _, record := range s.DB.Records {
+		c := &record.Candidate
+		if !s.Contactable(c) || !matches(record, query) {
+			continue
+		}
+		view := Candidate{Record: record, Login: c.Login(), Score: c.Score(now)}
+		if view.Score < minScore {
+			continue
+		}
+		result = append(result, view)
+	}
+
+	sortCandidates(result, query.Get("sort"))
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func matches(r *candidate.Record, query map[string][]string) bool {
+	get := func(key string) string {
+		if values := query[key]; len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+
+	if status := get("status"); status != "" && !strings.EqualFold(status, string(r.Status)) {
+		return false
+	}
+	if tag := get("tag"); tag != "" && !r.HasTag(tag) {
+		return false
+	}
+	if repo := get("repo"); repo != "" && len(r.Candidate.Roles(repo)) == 0 {
+		return false
+	}
+	if role := get("role"); role != "" {
+		found := false
+		for _, have := range r.Candidate.Roles("") {
+			found = found || strings.EqualFold(role, string(have))
+		}
+		if !found {
+			return false
+		}
+	}
+	if q := strings.ToLower(get("q")); q != "" {
+		u := r.Candidate.User
+		haystack := strings.ToLower(strings.Join([]string{string(u.Login), string(u.Name), string(u.Location),
+			string(u.Company)}, " "))
+		if !strings.Contains(haystack, q) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sortCandidates(candidates []Candidate, by string) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		switch by {
+		case "login":
+			return strings.ToLower(candidates[i].Login) < strings.ToLower(candidates[j].Login)
+		case "last_seen":
+			return candidates[i].LastSeen.After(candidates[j].LastSeen)
+		default:
+			return candidates[i].Score > candidates[j].Score
+		}
+	})
+}
+
+// handleCandidate returns a candidate on GET /api/candidates/<login>, sets their status
+// on POST or PUT /api/candidates/<login>/status {"status": "contacted"} and puts them on the
+// do-not-contact list on POST /api/candidates/<login>/dnc {"reason": "asked by email"}
+func (s *Server) handleCandidate(w http.ResponseWriter, r *http.Request) {
+	path := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/candidates/"), "/")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, found := s.DB.Find(path[0])
+	if !found || !s.Contactable(&record.Candidate) {
+		writeError(w, http.StatusNotFound, errors.New("no such candidate"))
+		return
+	}
+
+	switch {
+	case len(path) == 1 && r.Method == http.MethodGet:
+
+	case len(path) == 2 && path[1] == "status" && (r.Method == http.MethodPost || r.Method == http.MethodPut):
+		var req struct{ Status string }
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, errors.Wrap(err, "invalid request"))
+			return
+		}
+		status, err := candidate.ParseStatus(req.Status)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		record.SetStatus(status, s.Now())
+		if err = s.Save(s.DB); err != nil {
+			writeError(w, http.StatusInternalServerError, errors.Wrap(err, "couldn't save the status"))
+			return
+		}
+
+	case len(path) == 2 && path[1] == "dnc" && r.Method == http.MethodPost && s.DoNotContact != nil:
+		var req struct{ Reason string }
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, errors.Wrap(err, "invalid request"))
+			return
+		}
+		if err := s.DoNotContact(&record.Candidate, req.Reason); err != nil {
+			writeError(w, http.StatusInternalServerError, errors.Wrap(err, "couldn't update the do-not-contact list"))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+
+	default:
+		writeError(w, http.StatusNotFound, errors.New("no such route"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, Candidate{Record: record, Login: record.Candidate.Login(),
+		Score: record.Candidate.Score(s.Now())})
+}
@@ -1,45 +1,61 @@
 package cache
 
 import (
-	"bytes"
 	"crypto/md5"
-	"encoding/gob"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"time"
 
 	"github.com/birkelund/boltdbcache"
+	bolt "github.com/coreos/bbolt"
 
 	"github.com/pkg/errors"
+	"github.com/shurcooL/githubv4"
 
 	"github.com/gregjones/httpcache"
 )
 
+// boltBucket is the bucket boltdbcache keeps its items in
+const boltBucket = "httpcache"
+
 type Cache struct {
 	httpcache.Cache
 	validity time.Duration
+	db       *bolt.DB
 }
 
 func NewCache(bucketName string, validity time.Duration) (cache *Cache, err error) {
 	if cacheDir, err := os.UserCacheDir(); err != nil {
 		return nil, err
 	} else {
-		c, err := boltdbcache.New(filepath.Join(cacheDir, bucketName))
+		db, err := bolt.Open(filepath.Join(cacheDir, bucketName), 0600, nil)
+		if err != nil {
+			return nil, err
+		}
+		err = db.Update(func(tx *bolt.Tx) error {
+			_, err := tx.CreateBucketIfNotExists([]byte(boltBucket))
+			return err
+		})
 		if err != nil {
+			db.Close()
 			return nil, err
 		}
-		cache = &Cache{validity: validity, Cache: c}
+		cache = &Cache{validity: validity, Cache: boltdbcache.NewWithDB(db), db: db}
 	}
 	return
 }
 
 type payload struct {
 	CreationTime time.Time
-	Query        interface{}
+	Query        json.RawMessage
 }
 
+// WriteQuery caches the query's result for the given variables
 func (cache Cache) WriteQuery(q interface{}, variables map[string]interface{}) error {
 	hash, err := getHashForCall(q, variables)
 	if err != nil {
@@ -48,22 +64,63 @@ func (cache Cache) WriteQuery(q interface{}, variables map[string]interface{}) e
 
 	cacheKey := fmt.Sprintf("query-%s", hash)
 
-	gob.Register(q)
-
-	payload := payload{CreationTime: time.Now(), Query: q}
+	if path := unsetURI(reflect.ValueOf(q), reflect.TypeOf(q).String()); path != "" {
+		return errors.Errorf("cache data encoding error: %s is an unset URI, which can't be encoded", path)
+	}
 
-	var buf bytes.Buffer
-	encoder := gob.NewEncoder(&buf)
+	query, err := json.Marshal(q)
+	if err != nil {
+		return errors.Wrap(err, "cache data encoding error")
+	}
 
-	if err = encoder.Encode(payload); err != nil {
+	data, err := json.Marshal(payload{CreationTime: time.Now(), Query: query})
+	if err != nil {
 		return errors.Wrap(err, "cache data encoding error")
 	}
 
-	cache.Set(cacheKey, buf.Bytes())
+	cache.Set(cacheKey, data)
 
 	return nil
 }
 
+// uriType is githubv4's URI scalar, which panics when its zero value is encoded
+var uriType = reflect.TypeOf(githubv4.URI{})
+
+// unsetURI returns the path to the first zero githubv4.URI found in v, or "" if there's none
+func unsetURI(v reflect.Value, path string) string {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return ""
+		}
+		return unsetURI(v.Elem(), path)
+	case reflect.Struct:
+		if v.Type() == uriType {
+			if v.Field(0).IsNil() {
+				return path
+			}
+			return ""
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if field := v.Type().Field(i); field.PkgPath == "" { // unexported fields aren't encoded
+				if found := unsetURI(v.Field(i), path+"."+field.Name); found != "" {
+					return found
+				}
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if found := unsetURI(v.Index(i), fmt.Sprintf("%s[%d]", path, i)); found != "" {
+				return found
+			}
+		}
+	}
+
+	return ""
+}
+
+// ReadQuery returns a fresh copy of the cached result of the query for the given variables,
+// having the same type as q
 func (cache Cache) ReadQuery(q interface{}, variables map[string]interface{}) (interface{}, error) {
 	hash, err := getHashForCall(q, variables)
 	if err != nil {
@@ -76,31 +133,50 @@ func (cache Cache) ReadQuery(q interface{}, variables map[string]interface{}) (i
 		return nil, fmt.Errorf("no cache for key %s", cacheKey)
 	}
 
-	buf := bytes.NewBuffer(item)
-
-	decoder := gob.NewDecoder(buf)
-	empty := payload{}
-	if err = decoder.Decode(&empty); err != nil {
+	var cached payload
+	if err = json.Unmarshal(item, &cached); err != nil {
 		return nil, errors.Wrap(err, "cache unmarshaling error")
 	}
 
-	if time.Since(empty.CreationTime) > cache.validity {
+	if time.Since(cached.CreationTime) > cache.validity {
 		return nil, fmt.Errorf("cache expired for key %s", cacheKey)
 	}
 
-	return empty.Query, nil
+	result := reflect.New(reflect.TypeOf(q))
+	if err = json.Unmarshal(cached.Query, result.Interface()); err != nil {
+		return nil, errors.Wrap(err, "cache unmarshaling error")
+	}
+
+	return result.Elem().Interface(), nil
 }
 
-// getHashForCall returns a hash for a specific query - variables combination
+// getHashForCall returns a hash for a specific query - variables combination.
+// The query's type (including its graphql tags) and the variables' values, in key order, are hashed.
 func getHashForCall(q interface{}, variables map[string]interface{}) (string, error) {
-	var buf bytes.Buffer
-	enc := gob.NewEncoder(&buf)
-
-	if err1, err2 := enc.Encode(q), enc.Encode(variables); err1 != nil || err2 != nil {
-		return "", errors.New("error while serializing data in order to be written in the cache")
+	if q == nil {
+		return "", errors.New("can't compute the hash of a nil query")
 	}
 
-	sum := md5.Sum(buf.Bytes())
+	h := md5.New()
+	fmt.Fprintf(h, "%s\n", reflect.TypeOf(q))
+
+	keys := make([]string, 0, len(variables))
+	for key := range variables {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := variables[key]
+		if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				value = nil
+			} else {
+				value = v.Elem().Interface()
+			}
+		}
+		fmt.Fprintf(h, "%s=%#v\n", key, value)
+	}
 
-	return hex.EncodeToString(sum[:]), nil
+	return hex.EncodeToString(h.Sum(nil)), nil
 }
@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	bolt "github.com/coreos/bbolt"
+)
+
+// Prune deletes the cached queries older than maxAge, returning how many were deleted
+func (cache Cache) Prune(maxAge time.Duration) (int, error) {
+	return cache.deleteWhere(func(p payload) bool {
+		return time.Since(p.CreationTime) > maxAge
+	})
+}
+
+// Erase deletes the cached queries whose results mention any of the given logins or ids,
+// returning how many were deleted. Logins are matched case insensitively.
+func (cache Cache) Erase(logins []string, ids []string) (int, error) {
+	var needles [][]byte
+	for _, login := range logins {
+		if login != "" {
+			needles = append(needles, jsonField("login", login))
+		}
+	}
+	for _, id := range ids {
+		if id != "" {
+			needles = append(needles, jsonField("id", id))
+		}
+	}
+
+	return cache.deleteWhere(func(p payload) bool {
+		query := bytes.ToLower(p.Query)
+		for _, needle := range needles {
+			if bytes.Contains(query, needle) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// jsonField returns the lowercase json encoding of a string field, as found in the cached queries
+func jsonField(name, value string) []byte {
+	encoded, _ := json.Marshal(value)
+	return bytes.ToLower(append([]byte(`"`+name+`":`), encoded...))
+}
+
+func (cache Cache) deleteWhere(match func(p payload) bool) (deleted int, err error) {
+	err = cache.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket([]byte(boltBucket))
+		if bkt == nil {
+			return nil
+		}
+
+		var keys [][]byte
+		err := bkt.ForEach(func(k, v []byte) error {
+			var p payload
+			if json.Unmarshal(v, &p) == nil && match(p) {
+				keys = append(keys, append([]byte{}, k...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, k := range keys {
+			if err := bkt.Delete(k); err != nil {
+				return err
+			}
+		}
+		deleted = len(keys)
+
+		return nil
+	})
+
+	return
+}
+
+// Close releases the cache's database
+func (cache Cache) Close() error {
+	return cache.db.Close()
+}
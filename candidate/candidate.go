@@ -0,0 +1,270 @@
+package candidate
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/fetch"
+	"github.com/florinutz/gh-recruiter/graph"
+	"github.com/florinutz/gh-recruiter/timezone"
+)
+
+// Role is the way a user interacted with a repo
+type Role string
+
+// The roles a user can have in a repo
+const (
+	Forker    Role = "forker"
+	Committer Role = "committer"
+	Reviewer  Role = "reviewer"
+	Commenter Role = "commenter"
+	Stargazer Role = "stargazer"
+)
+
+// Roles lists the roles, in the order they're reported
+var Roles = []Role{Forker, Committer, Reviewer, Commenter, Stargazer}
+
+// Interaction is one of the user's interactions with a repo
+type Interaction struct {
+	// Repo is the repo's owner/name
+	Repo string
+	Role Role
+	// URL points to the interaction itself (comment, review, commit), when there is one
+	URL string `json:",omitempty"`
+}
+
+var prURL = regexp.MustCompile(`^https://github\.com/([^/]+/[^/]+)/pull/(\d+)`)
+
+// Label shortens the interaction's url, the PR ones becoming owner/name#number
+func (i Interaction) Label() string {
+	if m := prURL.FindStringSubmatch(i.URL); m != nil {
+		return m[1] + "#" + m[2]
+	}
+
+	return i.URL
+}
+
+// Candidate is a user together with the way they interacted with the analyzed repos
+type Candidate struct {
+	User         fetch.User
+	Interactions []Interaction
+	// Centrality is set when the candidate is part of a collaboration graph
+	Centrality *graph.Metrics `json:",omitempty"`
+	// Languages is set when the candidate's language profile was fetched
+	Languages fetch.LanguageProfile `json:",omitempty"`
+	// Activity is set when the candidate's contributions and recency were fetched
+	Activity *fetch.Activity `json:",omitempty"`
+	// Timezone is set when the candidate's timezone was inferred from their commits
+	Timezone *timezone.Estimate `json:",omitempty"`
+	// Status is set when the candidate is tracked in the pipeline
+	Status Status `json:",omitempty"`
+}
+
+// Login returns the candidate's login
+func (c *Candidate) Login() string {
+	return string(c.User.Login)
+}
+
+// Repos returns the repos the candidate interacted with, sorted
+func (c *Candidate) Repos() (repos []string) {
+	seen := map[string]bool{}
+	for _, i := range c.Interactions {
+		if !seen[i.Repo] {
+			seen[i.Repo] = true
+			repos = append(repos, i.Repo)
+		}
+	}
+	sort.Strings(repos)
+
+	return
+}
+
+// Roles returns the distinct roles the candidate had in the repo, or in all repos if repo is empty
+func (c *Candidate) Roles(repo string) (roles []Role) {
+	seen := map[Role]bool{}
+	for _, i := range c.Interactions {
+		if (repo == "" || i.Repo == repo) && !seen[i.Role] {
+			seen[i.Role] = true
+			roles = append(roles, i.Role)
+		}
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i] < roles[j] })
+
+	return
+}
+
+// LastActive returns the last time the candidate was active, or the zero time if it's unknown
+func (c *Candidate) LastActive() time.Time {
+	if c.Activity == nil {
+		return time.Time{}
+	}
+
+	return c.Activity.LastActive()
+}
+
+// Summary describes the candidate's roles per repo, e.g. "hashicorp/hcl: forker, reviewer"
+func (c *Candidate) Summary() string {
+	var parts []string
+	for _, repo := range c.Repos() {
+		var roles []string
+		for _, role := range c.Roles(repo) {
+			roles = append(roles, string(role))
+		}
+		parts = append(parts, repo+": "+strings.Join(roles, ", "))
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+// TopLanguagesCount is how many of the top languages are shown
+const TopLanguagesCount = 3
+
+// FormatForCsv returns the user's csv columns followed by their activity, the score, the interactions summary, the top languages,
+// the inferred timezone and the centrality metrics
+func (c *Candidate) FormatForCsv() []string {
+	activity := make([]string, len(fetch.ActivityCsvHeader))
+	if c.Activity != nil {
+		activity = c.Activity.FormatForCsv()
+	}
+	tz, degree, pageRank := "", "", ""
+	if c.Timezone != nil {
+		tz = c.Timezone.String()
+	}
+	if c.Centrality != nil {
+		degree = strconv.Itoa(c.Centrality.Degree)
+		pageRank = strconv.FormatFloat(c.Centrality.PageRank, 'f', 6, 64)
+	}
+
+	return append(append(c.User.FormatForCsv(), activity...),
+		strconv.FormatFloat(c.Score(time.Now()), 'f', 2, 64),
+		c.Summary(),
+		strings.Join(c.Languages.Top(TopLanguagesCount), " "),
+		tz,
+		degree,
+		pageRank,
+		string(c.Status),
+	)
+}
+
+// CsvHeader is the header matching Candidate.FormatForCsv
+var CsvHeader = append(append(append([]string{}, fetch.UserCsvHeader...), fetch.ActivityCsvHeader...),
+	"Score", "Interactions", "Top languages", "Timezone", "Degree", "PageRank", "Status")
+
+// Set holds candidates merged by login, in the order they were first added
+type Set struct {
+	byLogin map[string]*Candidate
+	logins  []string
+}
+
+// NewSet returns an empty set
+func NewSet() *Set {
+	return &Set{byLogin: map[string]*Candidate{}}
+}
+
+// Put merges the candidate into the set, keeping their derived data, and returns the merged candidate
+func (s *Set) Put(c *Candidate) *Candidate {
+	merged := s.Add(c.User, c.Interactions...)
+	if c.Centrality != nil {
+		merged.Centrality = c.Centrality
+	}
+	if c.Languages != nil {
+		merged.Languages = c.Languages
+	}
+	if c.Activity != nil {
+		merged.Activity = c.Activity
+	}
+	if c.Timezone != nil {
+		merged.Timezone = c.Timezone
+	}
+	if c.Status != "" {
+		merged.Status = c.Status
+	}
+
+	return merged
+}
+
+// Add merges the user and their interactions into the set, returning the merged candidate
+func (s *Set) Add(user fetch.User, interactions ...Interaction) *Candidate {
+	login := string(user.Login)
+	c, ok := s.byLogin[login]
+	if !ok {
+		c = &Candidate{User: user}
+		s.byLogin[login] = c
+		s.logins = append(s.logins, login)
+	} else if user.ID != nil {
+		c.User = user // keep the freshest profile
+	}
+
+	for _, i := range interactions {
+		if !c.has(i) {
+			c.Interactions = append(c.Interactions, i)
+		}
+	}
+
+	return c
+}
+
+func (c *Candidate) has(interaction Interaction) bool {
+	for _, i := range c.Interactions {
+		if i == interaction {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Merge adds all of other's candidates to the set
+func (s *Set) Merge(other *Set) {
+	for _, c := range other.All() {
+		s.Put(c)
+	}
+}
+
+// Get returns the candidate with the given login
+func (s *Set) Get(login string) (*Candidate, bool) {
+	c, ok := s.byLogin[login]
+	return c, ok
+}
+
+// Len returns the number of candidates in the set
+func (s *Set) Len() int {
+	return len(s.logins)
+}
+
+// All returns the candidates in the order they were added
+func (s *Set) All() []*Candidate {
+	result := make([]*Candidate, 0, len(s.logins))
+	for _, login := range s.logins {
+		result = append(result, s.byLogin[login])
+	}
+
+	return result
+}
+
+// ByScore returns the candidates, the best scored first
+func (s *Set) ByScore(now time.Time) []*Candidate {
+	all := s.All()
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].Score(now) > all[j].Score(now)
+	})
+
+	return all
+}
+
+// InRepos returns the candidates who interacted with at least min of the repos, the ones in most repos first
+func (s *Set) InRepos(min int) (result []*Candidate) {
+	for _, c := range s.All() {
+		if len(c.Repos()) >= min {
+			result = append(result, c)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return len(result[i].Repos()) > len(result[j].Repos())
+	})
+
+	return
+}
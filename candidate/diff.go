@@ -0,0 +1,123 @@
+package candidate
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/florinutz/gh-recruiter/fetch"
+)
+
+// ChangeKind tells how a candidate changed between two crawls
+type ChangeKind string
+
+// The kinds of changes between two crawls
+const (
+	Added   ChangeKind = "new"
+	Dropped ChangeKind = "dropped"
+	Changed ChangeKind = "changed"
+)
+
+// Change is a difference between two crawls, with Field, Before and After set for changed profiles.
+// After may also hold the repo a new candidate was found in
+type Change struct {
+	Kind   ChangeKind
+	Login  string
+	Field  string `json:",omitempty"`
+	Before string `json:",omitempty"`
+	After  string `json:",omitempty"`
+}
+
+// ChangeCsvHeader is the header matching Change.FormatForCsv
+var ChangeCsvHeader = []string{"Change", "Login", "Field", "Before", "After"}
+
+// FormatForCsv returns the change as a csv row
+func (c Change) FormatForCsv() []string {
+	return []string{string(c.Kind), c.Login, c.Field, c.Before, c.After}
+}
+
+// ProfileFields are the profile fields that can be compared, by name
+var ProfileFields = map[string]func(u fetch.User) string{
+	"login":    func(u fetch.User) string { return string(u.Login) },
+	"name":     func(u fetch.User) string { return string(u.Name) },
+	"email":    func(u fetch.User) string { return string(u.Email) },
+	"location": func(u fetch.User) string { return string(u.Location) },
+	"company":  func(u fetch.User) string { return string(u.Company) },
+	"bio":      func(u fetch.User) string { return strings.Join(strings.Fields(string(u.Bio)), " ") },
+	"hireable": func(u fetch.User) string { return strconv.FormatBool(bool(u.IsHireable)) },
+	"organizations": func(u fetch.User) string {
+		var logins []string
+		for _, org := range u.Organizations.Nodes {
+			logins = append(logins, string(org.Login))
+		}
+		sort.Strings(logins)
+		return strings.Join(logins, " ")
+	},
+}
+
+// ProfileFieldNames returns the names of the comparable profile fields, sorted
+func ProfileFieldNames() (names []string) {
+	for name := range ProfileFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return
+}
+
+// DiffUsers returns the changes of the given fields, all of them if none is given, between two
+// snapshots of a user's profile
+func DiffUsers(before, after fetch.User, fields ...string) (changes []Change) {
+	if len(fields) == 0 {
+		fields = ProfileFieldNames()
+	}
+
+	for _, field := range fields {
+		value, ok := ProfileFields[field]
+		if !ok {
+			continue
+		}
+		if old, fresh := value(before), value(after); old != fresh {
+			changes = append(changes, Change{Kind: Changed, Login: string(after.Login), Field: field,
+				Before: old, After: fresh})
+		}
+	}
+
+	return
+}
+
+// Diff returns the candidates that appeared, disappeared or changed their profiles between two crawls.
+// Candidates are matched by their ids, so renames show up as changed logins.
+func Diff(before, after []*Candidate) (changes []Change) {
+	previous := map[string]*Candidate{}
+	for _, c := range before {
+		previous[c.key()] = c
+	}
+
+	for _, c := range after {
+		old, ok := previous[c.key()]
+		if !ok {
+			changes = append(changes, Change{Kind: Added, Login: c.Login()})
+			continue
+		}
+		delete(previous, c.key())
+		changes = append(changes, DiffUsers(old.User, c.User)...)
+	}
+
+	for _, c := range before {
+		if _, ok := previous[c.key()]; ok {
+			changes = append(changes, Change{Kind: Dropped, Login: c.Login()})
+		}
+	}
+
+	return
+}
+
+// key identifies the candidate across crawls
+func (c *Candidate) key() string {
+	if id, _ := c.User.ID.(string); id != "" {
+		return id
+	}
+
+	return strings.ToLower(c.Login())
+}
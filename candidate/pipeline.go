@@ -0,0 +1,248 @@
+package candidate
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/fetch"
+	"github.com/pkg/errors"
+)
+
+// Status is where a candidate is in the hiring pipeline
+type Status string
+
+// The pipeline statuses, New being the one of the candidates nobody acted upon
+const (
+	New          Status = "new"
+	Contacted    Status = "contacted"
+	Replied      Status = "replied"
+	Interviewing Status = "interviewing"
+	Rejected     Status = "rejected"
+	Hired        Status = "hired"
+)
+
+// Statuses are all the pipeline statuses, in pipeline order
+var Statuses = []Status{New, Contacted, Replied, Interviewing, Rejected, Hired}
+
+// ParseStatus validates a status name
+func ParseStatus(name string) (Status, error) {
+	for _, s := range Statuses {
+		if strings.EqualFold(string(s), name) {
+			return s, nil
+		}
+	}
+
+	return "", errors.Errorf("unknown status %q", name)
+}
+
+// EventKind is the kind of a pipeline event
+type EventKind string
+
+// The events recorded in a candidate's history
+const (
+	EventFound  EventKind = "found"
+	EventStatus EventKind = "status"
+	EventNote   EventKind = "note"
+	EventTag    EventKind = "tag"
+	EventUntag  EventKind = "untag"
+	// EventChanged records a change in the candidate's profile
+	EventChanged EventKind = "changed"
+)
+
+// Event is an entry of a candidate's pipeline history
+type Event struct {
+	At    time.Time
+	Kind  EventKind
+	Value string
+}
+
+// Record is a candidate tracked in the pipeline
+type Record struct {
+	// Candidate is the latest snapshot of the candidate
+	Candidate Candidate
+	Status    Status
+	Tags      []string `json:",omitempty"`
+	History   []Event
+	FirstSeen time.Time
+	LastSeen  time.Time
+}
+
+// RecordCsvHeader is the header matching Record.FormatForCsv
+var RecordCsvHeader = []string{"Login", "Name", "Location", "Status", "Tags", "Score", "Interactions",
+	"First seen", "Last seen", "Last change"}
+
+// FormatForCsv returns the record as a csv row
+func (r *Record) FormatForCsv() []string {
+	lastChange := ""
+	if len(r.History) > 0 {
+		lastChange = r.History[len(r.History)-1].At.Format("02-Jan-2006")
+	}
+
+	return []string{
+		r.Candidate.Login(),
+		string(r.Candidate.User.Name),
+		string(r.Candidate.User.Location),
+		string(r.Status),
+		strings.Join(r.Tags, " "),
+		strconv.FormatFloat(r.Candidate.Score(time.Now()), 'f', 2, 64),
+		r.Candidate.Summary(),
+		r.FirstSeen.Format("02-Jan-2006"),
+		r.LastSeen.Format("02-Jan-2006"),
+		lastChange,
+	}
+}
+
+func (r *Record) record(kind EventKind, value string, at time.Time) {
+	r.History = append(r.History, Event{At: at, Kind: kind, Value: value})
+}
+
+// Refresh replaces the candidate's profile with a freshly fetched one, recording and returning
+// the changes of the given fields, all of them if none is given
+func (r *Record) Refresh(user fetch.User, at time.Time, fields ...string) []Change {
+	changes := DiffUsers(r.Candidate.User, user, fields...)
+	for _, c := range changes {
+		r.record(EventChanged, fmt.Sprintf("%s: %q -> %q", c.Field, c.Before, c.After), at)
+	}
+	r.Candidate.User = user
+
+	return changes
+}
+
+// SetStatus moves the candidate to the status
+func (r *Record) SetStatus(status Status, at time.Time) {
+	r.Status = status
+	r.Candidate.Status = status
+	r.record(EventStatus, string(status), at)
+}
+
+// AddNote adds a free text note to the candidate's history
+func (r *Record) AddNote(note string, at time.Time) {
+	r.record(EventNote, note, at)
+}
+
+// Tag adds the tag to the candidate, if they don't have it already
+func (r *Record) Tag(tag string, at time.Time) {
+	if r.HasTag(tag) {
+		return
+	}
+	r.Tags = append(r.Tags, tag)
+	sort.Strings(r.Tags)
+	r.record(EventTag, tag, at)
+}
+
+// Untag removes the tag from the candidate, if they have it
+func (r *Record) Untag(tag string, at time.Time) {
+	if !r.HasTag(tag) {
+		return
+	}
+	kept := r.Tags[:0]
+	for _, t := range r.Tags {
+		if t != tag {
+			kept = append(kept, t)
+		}
+	}
+	r.Tags = kept
+	r.record(EventUntag, tag, at)
+}
+
+// HasTag tells whether the candidate has the tag
+func (r *Record) HasTag(tag string) bool {
+	for _, t := range r.Tags {
+		if t == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DB holds the tracked candidates
+type DB struct {
+	Records []*Record
+}
+
+// Find returns the record of the candidate having the login or id
+func (db *DB) Find(loginOrID string) (*Record, bool) {
+	for _, r := range db.Records {
+		if id, _ := r.Candidate.User.ID.(string); id != "" && id == loginOrID {
+			return r, true
+		}
+	}
+	for _, r := range db.Records {
+		if strings.EqualFold(r.Candidate.Login(), loginOrID) {
+			return r, true
+		}
+	}
+
+	return nil, false
+}
+
+// Track records the candidate as seen, starting to track them if they're new.
+// The record's snapshot is refreshed and the candidate gets the status they have in the pipeline.
+func (db *DB) Track(c *Candidate, at time.Time) *Record {
+	id, _ := c.User.ID.(string)
+	r, found := db.Find(id)
+	if !found || id == "" {
+		r, found = db.Find(c.Login())
+	}
+
+	if !found {
+		r = &Record{Status: New, FirstSeen: at}
+		r.record(EventFound, strings.Join(c.Repos(), " "), at)
+		db.Records = append(db.Records, r)
+	}
+
+	previous := r.Candidate
+	r.Candidate = *c
+	r.Candidate.Interactions = append([]Interaction{}, previous.Interactions...)
+	for _, i := range c.Interactions {
+		if !r.Candidate.has(i) {
+			r.Candidate.Interactions = append(r.Candidate.Interactions, i)
+		}
+	}
+	if r.Candidate.Languages == nil {
+		r.Candidate.Languages = previous.Languages
+	}
+	if r.Candidate.Activity == nil {
+		r.Candidate.Activity = previous.Activity
+	}
+	if r.Candidate.Timezone == nil {
+		r.Candidate.Timezone = previous.Timezone
+	}
+	r.LastSeen = at
+	r.Candidate.Status = r.Status
+	c.Status = r.Status
+
+	return r
+}
+
+// Remove stops tracking the candidate having the login or id, telling whether they were tracked
+func (db *DB) Remove(loginOrID string) bool {
+	r, found := db.Find(loginOrID)
+	if !found {
+		return false
+	}
+
+	kept := db.Records[:0]
+	for _, other := range db.Records {
+		if other != r {
+			kept = append(kept, other)
+		}
+	}
+	db.Records = kept
+
+	return true
+}
+
+// Sorted returns the records, the most recently seen first
+func (db *DB) Sorted() []*Record {
+	sorted := append([]*Record{}, db.Records...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].LastSeen.After(sorted[j].LastSeen)
+	})
+
+	return sorted
+}
@@ -0,0 +1,39 @@
+package candidate
+
+import (
+	"math"
+	"time"
+)
+
+// RoleWeights says how much each kind of interaction tells about a candidate
+var RoleWeights = map[Role]float64{
+	Committer: 5,
+	Reviewer:  4,
+	Commenter: 2,
+	Forker:    1,
+	Stargazer: 0.5,
+}
+
+// Score ranks the candidate: their interactions with the analyzed repos, their contributions of the
+// last year and their followers count, all decaying with the time since they were last active.
+// The contributions and the decay only count when the candidate's activity was fetched.
+func (c *Candidate) Score(now time.Time) float64 {
+	interactions := 0.0
+	for _, i := range c.Interactions {
+		interactions += RoleWeights[i.Role]
+	}
+
+	score := interactions + math.Log1p(float64(c.User.Followers.TotalCount))
+	if c.Activity != nil {
+		score += 3 * math.Log1p(float64(c.Activity.Contributions.Total()))
+	}
+
+	if last := c.LastActive(); !last.IsZero() {
+		idleYears := now.Sub(last).Hours() / 24 / 365
+		if idleYears > 0 {
+			score /= 1 + idleYears
+		}
+	}
+
+	return math.Round(score*100) / 100
+}
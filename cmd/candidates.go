@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/output"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	candidatesFlagStatus = "status"
+	candidatesFlagTag    = "tag"
+	candidatesFlagRemove = "remove"
+)
+
+var candidatesFlags struct {
+	status, tag string
+	remove      bool
+}
+
+// candidateDB is the candidate pipeline, nil if there's no state store
+var candidateDB *candidate.DB
+
+// candidatesCmd manages the candidate pipeline
+var candidatesCmd = &cobra.Command{
+	Use:   "candidates",
+	Short: "tracks the reported candidates through the hiring pipeline",
+	Long: fmt.Sprintf(`Every reported candidate is tracked in a local pipeline, starting with the %s status.
+The crawls show the candidates' statuses, so nobody gets contacted twice.`, candidate.New),
+	PersistentPreRun: preRunCandidates,
+}
+
+var candidatesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "lists the tracked candidates, the most recently seen first",
+	Run:   runCandidatesList,
+	Args:  cobra.NoArgs,
+}
+
+var candidatesShowCmd = &cobra.Command{
+	Use:   "show <login>",
+	Short: "shows a candidate's profile and history",
+	Run:   runCandidatesShow,
+	Args:  cobra.ExactArgs(1),
+}
+
+var candidatesSetStatusCmd = &cobra.Command{
+	Use:       "set-status <login> <status>",
+	Short:     "moves a candidate through the pipeline",
+	Example:   "gh-recruiter candidates set-status someone contacted",
+	ValidArgs: statusNames(),
+	Run:       runCandidatesSetStatus,
+	Args:      cobra.ExactArgs(2),
+}
+
+var candidatesNoteCmd = &cobra.Command{
+	Use:     "note <login> <text>...",
+	Short:   "adds a note to a candidate's history",
+	Example: `gh-recruiter candidates note someone "prefers remote, available from march"`,
+	Run:     runCandidatesNote,
+	Args:    cobra.MinimumNArgs(2),
+}
+
+var candidatesTagCmd = &cobra.Command{
+	Use:     "tag <login> <tag>...",
+	Short:   "tags a candidate",
+	Example: "gh-recruiter candidates tag someone backend senior",
+	Run:     runCandidatesTag,
+	Args:    cobra.MinimumNArgs(2),
+}
+
+func init() {
+	candidatesListCmd.Flags().StringVar(&candidatesFlags.status, candidatesFlagStatus, "",
+		fmt.Sprintf("only list the candidates having this status, one of %s", strings.Join(statusNames(), ", ")))
+	candidatesListCmd.Flags().StringVar(&candidatesFlags.tag, candidatesFlagTag, "",
+		"only list the candidates having this tag")
+	candidatesTagCmd.Flags().BoolVar(&candidatesFlags.remove, candidatesFlagRemove, false,
+		"remove the tags instead")
+
+	candidatesCmd.AddCommand(candidatesListCmd, candidatesShowCmd, candidatesSetStatusCmd,
+		candidatesNoteCmd, candidatesTagCmd)
+	rootCmd.AddCommand(candidatesCmd)
+}
+
+func statusNames() (names []string) {
+	for _, s := range candidate.Statuses {
+		names = append(names, string(s))
+	}
+
+	return
+}
+
+func preRunCandidates(cmd *cobra.Command, args []string) {
+	preRunDnc(cmd, args)
+
+	var err error
+	if candidateDB, err = States.LoadCandidates(); err != nil {
+		log.WithError(err).Fatal("couldn't load the candidates")
+	}
+}
+
+// loadCandidateDB loads the candidate pipeline for the crawls, which track whoever they report
+func loadCandidateDB() {
+	if States == nil {
+		return
+	}
+
+	var err error
+	if candidateDB, err = States.LoadCandidates(); err != nil {
+		log.WithError(err).Fatal("couldn't load the candidates")
+	}
+}
+
+// trackCandidate records the candidate in the pipeline, giving them their pipeline status
+func trackCandidate(c *candidate.Candidate) {
+	if candidateDB != nil {
+		candidateDB.Track(c, time.Now())
+	}
+}
+
+// saveCandidateDB persists the candidate pipeline, if it was loaded, exiting if it can't
+func saveCandidateDB() {
+	if err := persistCandidateDB(); err != nil {
+		log.WithError(err).Fatal("couldn't save the candidates")
+	}
+}
+
+// persistCandidateDB persists the candidate pipeline, if it was loaded
+func persistCandidateDB() error {
+	if candidateDB == nil {
+		return nil
+	}
+
+	return States.SaveCandidates(candidateDB)
+}
+
+// mustFindRecord returns the tracked candidate having the login, exiting if there's none
+func mustFindRecord(login string) *candidate.Record {
+	r, found := candidateDB.Find(login)
+	if !found {
+		log.WithField("login", login).Fatal("not a tracked candidate")
+	}
+	if !contactable(r.Candidate.Login(), r.Candidate.User.ID) {
+		log.WithField("login", login).Fatal("on the do-not-contact list")
+	}
+
+	return r
+}
+
+func runCandidatesList(cmd *cobra.Command, args []string) {
+	var status candidate.Status
+	if candidatesFlags.status != "" {
+		var err error
+		if status, err = candidate.ParseStatus(candidatesFlags.status); err != nil {
+			log.WithError(err).Fatal()
+		}
+	}
+
+	out := mustStdout(candidate.RecordCsvHeader)
+	for _, r := range candidateDB.Sorted() {
+		if (status != "" && r.Status != status) || (candidatesFlags.tag != "" && !r.HasTag(candidatesFlags.tag)) {
+			continue
+		}
+		if !contactable(r.Candidate.Login(), r.Candidate.User.ID) {
+			continue
+		}
+		out.Write(r)
+	}
+	if err := out.Flush(); err != nil {
+		log.WithError(err).Fatal("couldn't print the candidates")
+	}
+}
+
+func runCandidatesShow(cmd *cobra.Command, args []string) {
+	r := mustFindRecord(args[0])
+	if rootConfig.format == output.FormatJSON {
+		out := mustStdout(nil)
+		out.Write(&r.Candidate)
+		out.Flush()
+		return
+	}
+
+	u := r.Candidate.User
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, field := range [][2]string{
+		{"Login", string(u.Login)},
+		{"Name", string(u.Name)},
+		{"Email", string(u.Email)},
+		{"Location", string(u.Location)},
+		{"Company", string(u.Company)},
+		{"Bio", strings.Join(strings.Fields(string(u.Bio)), " ")},
+		{"Status", string(r.Status)},
+		{"Tags", strings.Join(r.Tags, " ")},
+		{"Score", fmt.Sprintf("%.2f", r.Candidate.Score(time.Now()))},
+		{"Interactions", r.Candidate.Summary()},
+	} {
+		fmt.Fprintf(w, "%s:\t%s\n", field[0], field[1])
+	}
+	fmt.Fprintln(w, "\nHistory:")
+	for _, e := range r.History {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", e.At.Format("02-Jan-2006 15:04"), e.Kind, e.Value)
+	}
+	fmt.Fprintln(w, "\nLinks:")
+	for _, i := range r.Candidate.Interactions {
+		if i.URL != "" {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", i.Repo, i.Role, i.URL)
+		}
+	}
+	w.Flush()
+}
+
+func runCandidatesSetStatus(cmd *cobra.Command, args []string) {
+	status, err := candidate.ParseStatus(args[1])
+	if err != nil {
+		log.WithError(err).Fatal()
+	}
+	mustFindRecord(args[0]).SetStatus(status, time.Now())
+	saveCandidateDB()
+}
+
+func runCandidatesNote(cmd *cobra.Command, args []string) {
+	mustFindRecord(args[0]).AddNote(strings.Join(args[1:], " "), time.Now())
+	saveCandidateDB()
+}
+
+func runCandidatesTag(cmd *cobra.Command, args []string) {
+	r := mustFindRecord(args[0])
+	for _, tag := range args[1:] {
+		if candidatesFlags.remove {
+			r.Untag(tag, time.Now())
+		} else {
+			r.Tag(tag, time.Now())
+		}
+	}
+	saveCandidateDB()
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/florinutz/gh-recruiter/export"
 	"github.com/pelletier/go-toml"
 
 	"github.com/pkg/errors"
@@ -50,6 +51,30 @@ func runConfig(cmd *cobra.Command, args []string) {
 			Forkers: false,
 			Csv:     "/tmp/testing_this_",
 		},
+		Exclude: Exclusions{
+			Orgs:      []string{"my-company"},
+			Companies: []string{`my company`, `^@?friendly-corp\b`},
+		},
+		Retention: Retention{Days: 90},
+		Daemon: DaemonConfig{
+			Listen:       "127.0.0.1:8089",
+			MinRateLimit: 500,
+			Schedule: []ScheduledCrawl{
+				{Cron: "0 6 * * 1", Repo: "hashicorp/hcl"},
+				{Cron: "@daily", Org: "zclconf"},
+			},
+		},
+		Exports: []export.Profile{
+			{
+				Name:   "short",
+				Format: export.FormatCsv,
+				Columns: []export.Column{
+					{Header: "Name", Value: "{{.Name}}"},
+					{Header: "Email", Value: "{{.Email}}"},
+					{Header: "Summary", Value: "{{.Roles}} ({{join .TopLanguages \", \"}})"},
+				},
+			},
+		},
 		Repos: []*repo{
 			{
 				Owner: "hashicorp",
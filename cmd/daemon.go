@@ -0,0 +1,296 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/fetch"
+	"github.com/florinutz/gh-recruiter/notify"
+	"github.com/florinutz/gh-recruiter/schedule"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const daemonFlagListen = "listen"
+
+var daemonListen string
+
+// DaemonConfig schedules the crawls the daemon runs
+type DaemonConfig struct {
+	Listen       string           `toml:"listen" comment:"address the status endpoint listens on"`
+	MinRateLimit int              `toml:"min_rate_limit" mapstructure:"min_rate_limit" comment:"crawls wait for the rate limit to reset when fewer points remain"`
+	Schedule     []ScheduledCrawl `toml:"schedule" comment:"the crawls, each having a cron expression and either a repo or an org"`
+}
+
+// ScheduledCrawl is a crawl of a repo or of an organization's repos, run on a cron schedule
+type ScheduledCrawl struct {
+	Cron string `toml:"cron"`
+	Repo string `toml:"repo" comment:"owner/name" omitempty:"true"`
+	Org  string `toml:"org" omitempty:"true"`
+}
+
+// Target returns the crawled repo or org
+func (s ScheduledCrawl) Target() string {
+	if s.Repo != "" {
+		return s.Repo
+	}
+
+	return s.Org
+}
+
+// job is a scheduled crawl together with its status
+type job struct {
+	ScheduledCrawl
+	cron *schedule.Cron
+
+	Next           time.Time
+	Running        bool
+	LastRun        time.Time `json:",omitempty"`
+	LastDuration   string    `json:",omitempty"`
+	LastError      string    `json:",omitempty"`
+	LastCandidates int
+	LastNew        int
+}
+
+// daemon runs the scheduled crawls one at a time, so they share the rate limit
+type daemon struct {
+	mu        sync.Mutex
+	Started   time.Time
+	Jobs      []*job
+	RateLimit *fetch.RateLimit `json:",omitempty"`
+
+	sinks notify.Sink
+}
+
+// daemonCmd runs the scheduled crawls
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "runs the crawls scheduled in the config",
+	Long: `Runs the crawls scheduled in the daemon section of the config, one at a time and incrementally,
+waiting for the rate limit to reset when it runs low. New candidates are printed and sent to the
+notification sinks, while GET /status on the listen address shows the state of the crawls.`,
+	Example: `[daemon]
+  listen = "127.0.0.1:8089"
+  [[daemon.schedule]]
+    cron = "0 6 * * 1"
+    repo = "hashicorp/hcl"
+  [[daemon.schedule]]
+    cron = "@daily"
+    org = "zclconf"`,
+	PreRun: preRunRepo,
+	Run:    runDaemon,
+	Args:   cobra.NoArgs,
+}
+
+func init() {
+	addCrawlFlags(daemonCmd)
+	daemonCmd.Flags().StringVar(&daemonListen, daemonFlagListen, "127.0.0.1:8089",
+		"address the status endpoint listens on, overriding the config")
+
+	rootCmd.AddCommand(daemonCmd)
+}
+
+func runDaemon(cmd *cobra.Command, args []string) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	d, err := newDaemon(RepoCmdConfig.Daemon.Schedule, time.Now())
+	if err != nil {
+		log.WithError(err).Fatal("invalid schedule")
+	}
+	d.sinks = buildSinks(RepoCmdConfig.Notify)
+	if cmd.Flags().Changed(daemonFlagListen) || RepoCmdConfig.Daemon.Listen == "" {
+		RepoCmdConfig.Daemon.Listen = daemonListen
+	}
+
+	// the failed crawls continue from their checkpoints the next time they run
+	repoFlags.resume = true
+
+	server := &http.Server{Addr: RepoCmdConfig.Daemon.Listen, Handler: d}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.WithError(err).Fatal("status endpoint failed")
+		}
+	}()
+	log.WithField("address", server.Addr).Info("daemon started")
+
+	d.loop(ctx)
+	server.Shutdown(context.Background())
+}
+
+func newDaemon(crawls []ScheduledCrawl, now time.Time) (*daemon, error) {
+	if len(crawls) == 0 {
+		return nil, errors.New("nothing is scheduled")
+	}
+
+	d := &daemon{Started: now}
+	for _, crawl := range crawls {
+		if (crawl.Repo == "") == (crawl.Org == "") {
+			return nil, errors.Errorf("scheduled crawl %q needs either a repo or an org", crawl.Cron)
+		}
+		if crawl.Repo != "" && len(strings.Split(crawl.Repo, "/")) != 2 {
+			return nil, errors.Errorf("invalid repo %q, expecting owner/name", crawl.Repo)
+		}
+		cron, err := schedule.ParseCron(crawl.Cron)
+		if err != nil {
+			return nil, err
+		}
+		d.Jobs = append(d.Jobs, &job{ScheduledCrawl: crawl, cron: cron, Next: cron.Next(now)})
+	}
+
+	return d, nil
+}
+
+// due returns the job to run next
+func (d *daemon) due() *job {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	next := d.Jobs[0]
+	for _, j := range d.Jobs[1:] {
+		if j.Next.Before(next.Next) {
+			next = j
+		}
+	}
+
+	return next
+}
+
+func (d *daemon) loop(ctx context.Context) {
+	for {
+		j := d.due()
+		log.WithFields(log.Fields{"target": j.Target(), "at": j.Next}).Info("next crawl")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Until(j.Next)):
+		}
+
+		d.run(ctx, j)
+	}
+}
+
+// run runs the job, recording how it went
+func (d *daemon) run(ctx context.Context, j *job) {
+	d.mu.Lock()
+	j.Running = true
+	d.mu.Unlock()
+
+	started := time.Now()
+	found, fresh, err := d.crawl(ctx, j.ScheduledCrawl)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	j.Running = false
+	j.LastRun = started
+	j.LastDuration = time.Since(started).Round(time.Second).String()
+	j.LastCandidates, j.LastNew, j.LastError = found, fresh, ""
+	if err != nil {
+		j.LastError = err.Error()
+		log.WithError(err).WithField("target", j.Target()).Error("scheduled crawl failed")
+	}
+	j.Next = j.cron.Next(time.Now())
+}
+
+// crawl runs a scheduled crawl as a recorded run, returning how many candidates it found and how many were new.
+// The rate limit is checked before each repo, a crawl of an org's repos being a long one.
+func (d *daemon) crawl(ctx context.Context, crawl ScheduledCrawl) (found, fresh int, err error) {
+	startRun("daemon", []string{crawl.Target()})
+	defer func() {
+		if err := persistCandidateDB(); err != nil {
+			log.WithError(err).Error("couldn't save the candidates")
+		}
+		saveRun()
+	}()
+
+	var repos []*repo
+	if crawl.Repo != "" {
+		ownerName := strings.Split(crawl.Repo, "/")
+		repos = append(repos, configuredRepo(ownerName[0], ownerName[1]))
+	} else {
+		if err = d.waitForRateLimit(ctx); err != nil {
+			return
+		}
+		summaries, err := Fetcher.GetOrgRepos(ctx, crawl.Org, fetch.NewPaginator(nil), orgFilter)
+		if err != nil {
+			return 0, 0, errors.Wrapf(err, "couldn't list %s's repos", crawl.Org)
+		}
+		for _, summary := range summaries {
+			repos = append(repos, configuredRepo(string(summary.Owner.Login), string(summary.Name)))
+		}
+	}
+
+	for _, r := range repos {
+		if err = d.waitForRateLimit(ctx); err != nil {
+			return
+		}
+		r.Incremental = true
+		if err = r.analyze(ctx); err != nil {
+			return found, fresh, errors.Wrap(err, r.NameWithOwner())
+		}
+		found += r.candidates.Len()
+		fresh += r.newCandidates.Len()
+		for _, c := range r.newCandidates.All() {
+			n := notify.Notification{Change: candidate.Change{Kind: candidate.Added, Login: c.Login(),
+				After: r.NameWithOwner()}, At: time.Now()}
+			if err := d.sinks.Notify(ctx, n); err != nil {
+				log.WithError(err).WithField("login", c.Login()).Warn("notification failed")
+			}
+		}
+		if err = r.reportNewCandidates(); err != nil {
+			return found, fresh, errors.Wrap(err, r.NameWithOwner())
+		}
+	}
+
+	return
+}
+
+// waitForRateLimit waits for the rate limit to reset if fewer than the configured points remain
+func (d *daemon) waitForRateLimit(ctx context.Context) error {
+	limit, err := Fetcher.GetRateLimit(ctx)
+	if err != nil {
+		return errors.Wrap(err, "couldn't check the rate limit")
+	}
+
+	d.mu.Lock()
+	d.RateLimit = &limit
+	d.mu.Unlock()
+
+	if int(limit.Remaining) >= RepoCmdConfig.Daemon.MinRateLimit {
+		return nil
+	}
+	log.WithFields(log.Fields{"remaining": limit.Remaining, "reset": limit.ResetAt}).Info("waiting for the rate limit")
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(time.Until(limit.ResetAt.Time)):
+		return nil
+	}
+}
+
+// ServeHTTP serves the daemon's status
+func (d *daemon) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/status" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(d)
+}
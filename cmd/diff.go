@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/state"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// The aliases of the two most recent runs
+const (
+	runLast     = "last"
+	runPrevious = "previous"
+)
+
+// diffCmd compares runs
+var diffCmd = &cobra.Command{
+	Use:   "diff [<runA> <runB>]",
+	Short: "shows the candidates that appeared, disappeared or changed between two crawls",
+	Long: `Every crawl is recorded as a run. Given two run ids, or the last and previous aliases,
+diff shows the new and dropped candidates and the changed profile fields (location, company,
+hireable...). Without arguments, it lists the recorded runs.`,
+	Example: "gh-recruiter diff previous last\ngh-recruiter diff 20181112-090000 20181119-090000",
+	PreRun:  preRunDnc,
+	Run:     runDiff,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return nil
+		}
+		return cobra.ExactArgs(2)(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+}
+
+// resolveRunID turns the run aliases into ids
+func resolveRunID(ids []string, id string) string {
+	switch {
+	case id == runLast && len(ids) > 0:
+		return ids[len(ids)-1]
+	case id == runPrevious && len(ids) > 1:
+		return ids[len(ids)-2]
+	}
+
+	return id
+}
+
+func runDiff(cmd *cobra.Command, args []string) {
+	ids, err := States.RunIDs()
+	if err != nil {
+		log.WithError(err).Fatal("couldn't list the runs")
+	}
+
+	if len(args) == 0 {
+		out := mustStdout(state.RunCsvHeader)
+		for _, id := range ids {
+			r, err := States.LoadRun(id)
+			if err != nil {
+				log.WithError(err).WithField("run", id).Warn("couldn't load run")
+				continue
+			}
+			out.Write(r)
+		}
+		if err = out.Flush(); err != nil {
+			log.WithError(err).Fatal("couldn't print the runs")
+		}
+		return
+	}
+
+	var runs [2]*state.Run
+	for i, arg := range args {
+		if runs[i], err = States.LoadRun(resolveRunID(ids, arg)); err != nil {
+			log.WithError(err).Fatal()
+		}
+	}
+
+	out := mustStdout(candidate.ChangeCsvHeader)
+	for _, change := range candidate.Diff(runs[0].Candidates, runs[1].Candidates) {
+		if !contactable(change.Login, nil) {
+			continue
+		}
+		out.Write(change)
+	}
+	if err = out.Flush(); err != nil {
+		log.WithError(err).Fatal("couldn't print the changes")
+	}
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/fetch"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	discoverFlagTopic    = "topic"
+	discoverFlagLanguage = "language"
+	discoverFlagMinStars = "min-stars"
+	discoverFlagArchived = "archived"
+	discoverFlagLimit    = "limit"
+	discoverFlagConfirm  = "confirm"
+)
+
+var discoverFlags struct {
+	search  fetch.RepoSearch
+	limit   int
+	confirm bool
+}
+
+// discoverCmd finds repos by topic and language and analyzes them
+var discoverCmd = &cobra.Command{
+	Use:     "discover",
+	Short:   "finds repos by topic and language, then filters users who interacted with them",
+	Example: "gh-recruiter discover --topic terraform --language go --min-stars 200 --prs",
+	PreRun:  preRunCrawl,
+	PostRun: postRunCrawl,
+	Run:     runDiscover,
+	Args:    cobra.NoArgs,
+}
+
+func init() {
+	addCrawlFlags(discoverCmd)
+	addXlsxFlag(discoverCmd)
+
+	discoverCmd.Flags().StringSliceVarP(&discoverFlags.search.Topics, discoverFlagTopic, "t", nil,
+		"repo topic, can be repeated")
+	discoverCmd.Flags().StringVarP(&discoverFlags.search.Language, discoverFlagLanguage, "l", "",
+		"repo language")
+	discoverCmd.Flags().IntVar(&discoverFlags.search.MinStars, discoverFlagMinStars, 0,
+		"only repos having at least this many stars")
+	discoverCmd.Flags().BoolVar(&discoverFlags.search.Archived, discoverFlagArchived, false,
+		"include archived repos")
+	discoverCmd.Flags().IntVar(&discoverFlags.limit, discoverFlagLimit, 10,
+		"analyze at most this many of the best ranked repos")
+	discoverCmd.Flags().BoolVar(&discoverFlags.confirm, discoverFlagConfirm, false,
+		"ask for confirmation before analyzing the discovered repos")
+
+	rootCmd.AddCommand(discoverCmd)
+}
+
+func runDiscover(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+	query := discoverFlags.search.Query()
+	if query == "archived:false" || query == "" {
+		log.Fatal("at least a topic, a language or a minimum number of stars is needed")
+	}
+
+	log.WithField("query", query).Info("searching repos")
+	pager := fetch.NewPaginator(nil)
+	if discoverFlags.limit > 0 {
+		// enough pages for the limit, the repos being ranked among them
+		pager.MaxPages = (discoverFlags.limit + fetch.SearchPageSize - 1) / fetch.SearchPageSize
+	}
+	repos, err := Fetcher.SearchRepos(ctx, query, pager)
+	if err != nil {
+		log.WithError(err).Fatal("repo search failed")
+	}
+
+	fetch.RankRepos(repos, time.Now())
+	if discoverFlags.limit > 0 && len(repos) > discoverFlags.limit {
+		repos = repos[:discoverFlags.limit]
+	}
+
+	printRepos(repos)
+	if len(repos) == 0 {
+		return
+	}
+	if discoverFlags.confirm && !confirm(fmt.Sprintf("analyze these %d repos?", len(repos))) {
+		return
+	}
+
+	candidates := analyzeRepos(ctx, repos)
+	printCandidates(candidates)
+
+	if RepoCmdConfig.Csv != "" {
+		writeCandidatesCsv(fmt.Sprintf("%s_discovered_candidates.csv", RepoCmdConfig.Csv), candidates)
+	}
+}
+
+// printRepos lists the repos with their ranking data on stderr, keeping stdout for the candidates
+func printRepos(repos []fetch.Repo) {
+	now := time.Now()
+	w := tabwriter.NewWriter(os.Stderr, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tRepo\tLanguage\tStars\tForks\tPushed\tScore")
+	for i, r := range repos {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%d\t%s\t%.0f\n", i+1, r.NameWithOwner(), r.PrimaryLanguage.Name,
+			r.Stargazers.TotalCount, r.ForkCount, r.PushedAt.Format("02-Jan-2006"), r.Score(now))
+	}
+	w.Flush()
+}
+
+// confirm asks a yes/no question on the terminal
+func confirm(question string) bool {
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "y" || answer == "yes"
+}
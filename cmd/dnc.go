@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/state"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/githubv4"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	dncFlagReason = "reason"
+	dncFlagID     = "id"
+)
+
+var dncFlags struct {
+	reason, id string
+}
+
+// doNotContact is the list of people that are never reported, nil if there's no state store
+var doNotContact *state.DoNotContact
+
+// doNotContactLogins holds the current logins of the people found on the do-not-contact list while crawling
+var doNotContactLogins sync.Map
+
+// dncCmd manages the do-not-contact list
+var dncCmd = &cobra.Command{
+	Use:   "dnc",
+	Short: "manages the list of people that asked not to be contacted",
+	Long: `People on the do-not-contact list are never reported, whatever the command.
+They're matched by their github node id, so they stay excluded after renaming their login.`,
+	PersistentPreRun: preRunDnc,
+}
+
+var dncAddCmd = &cobra.Command{
+	Use:     "add <login>...",
+	Short:   "adds people to the do-not-contact list",
+	Example: `gh-recruiter dnc add someone --reason "asked by email"`,
+	Run:     runDncAdd,
+	Args:    cobra.MinimumNArgs(1),
+}
+
+var dncRmCmd = &cobra.Command{
+	Use:   "rm <login or id>...",
+	Short: "removes people from the do-not-contact list",
+	Run:   runDncRm,
+	Args:  cobra.MinimumNArgs(1),
+}
+
+var dncLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "lists the do-not-contact list",
+	Run:   runDncLs,
+	Args:  cobra.NoArgs,
+}
+
+var dncImportCmd = &cobra.Command{
+	Use:   "import <csv>",
+	Short: "adds the people in a login,reason,id csv to the do-not-contact list",
+	Long: `Adds the people in the csv to the do-not-contact list. Only the login column is required,
+--reason is used for the rows having no reason. A header row starting with "login" is skipped.`,
+	Run:  runDncImport,
+	Args: cobra.ExactArgs(1),
+}
+
+func init() {
+	dncAddCmd.Flags().StringVar(&dncFlags.reason, dncFlagReason, "", "why they're not to be contacted")
+	dncAddCmd.Flags().StringVar(&dncFlags.id, dncFlagID, "", "their github node id, looked up if missing")
+	dncImportCmd.Flags().StringVar(&dncFlags.reason, dncFlagReason, "", "reason for the rows having none")
+
+	dncCmd.AddCommand(dncAddCmd, dncRmCmd, dncLsCmd, dncImportCmd)
+	rootCmd.AddCommand(dncCmd)
+}
+
+func preRunDnc(cmd *cobra.Command, args []string) {
+	var err error
+	if err = veep.Unmarshal(&RepoCmdConfig); err != nil {
+		log.WithError(err).Fatal("couldn't parse config")
+	}
+	if States, err = state.NewStore(stateDirName); err != nil {
+		log.WithError(err).Fatal("couldn't open the state store")
+	}
+	if doNotContact, err = States.LoadDoNotContact(); err != nil {
+		log.WithError(err).Fatal("couldn't load the do-not-contact list")
+	}
+}
+
+func runDncAdd(cmd *cobra.Command, args []string) {
+	if dncFlags.id != "" && len(args) > 1 {
+		log.Fatalf("--%s only goes with a single login", dncFlagID)
+	}
+
+	ctx := context.Background()
+	for _, login := range args {
+		id := dncFlags.id
+		if id == "" {
+			id = lookupID(ctx, login)
+		}
+		addToDoNotContact(login, id, dncFlags.reason)
+	}
+	saveDoNotContact()
+}
+
+func runDncRm(cmd *cobra.Command, args []string) {
+	for _, loginOrID := range args {
+		if !doNotContact.Remove(loginOrID) {
+			log.WithField("who", loginOrID).Warn("not on the do-not-contact list")
+		}
+	}
+	saveDoNotContact()
+}
+
+func runDncLs(cmd *cobra.Command, args []string) {
+	out := mustStdout(state.DoNotContactCsvHeader)
+	for _, e := range doNotContact.Entries {
+		out.Write(e)
+	}
+	if err := out.Flush(); err != nil {
+		log.WithError(err).Fatal("couldn't print the do-not-contact list")
+	}
+}
+
+func runDncImport(cmd *cobra.Command, args []string) {
+	f, err := os.Open(args[0])
+	if err != nil {
+		log.WithError(err).Fatal()
+	}
+	defer f.Close()
+
+	ctx := context.Background()
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	for first := true; ; first = false {
+		row, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.WithError(errors.Wrap(err, "invalid csv")).Fatal()
+		}
+		login := strings.TrimSpace(row[0])
+		if login == "" || (first && strings.EqualFold(login, "login")) {
+			continue
+		}
+
+		reason, id := dncFlags.reason, ""
+		if len(row) > 1 && strings.TrimSpace(row[1]) != "" {
+			reason = strings.TrimSpace(row[1])
+		}
+		if len(row) > 2 {
+			id = strings.TrimSpace(row[2])
+		}
+		if id == "" {
+			id = lookupID(ctx, login)
+		}
+		addToDoNotContact(login, id, reason)
+	}
+	saveDoNotContact()
+}
+
+// lookupID returns the user's github node id, or nothing if it can't be fetched
+func lookupID(ctx context.Context, login string) string {
+	if len(RepoCmdConfig.Tokens) == 0 {
+		log.WithField("login", login).Warn("no github token configured, only matching the login")
+		return ""
+	}
+	if Fetcher.Client == nil {
+		Fetcher = newFetcher(ctx)
+	}
+
+	user, err := Fetcher.GetUser(ctx, login)
+	if err != nil {
+		log.WithError(err).WithField("login", login).Warn("couldn't look up the id, only matching the login")
+		return ""
+	}
+	id, _ := user.ID.(string)
+
+	return id
+}
+
+func addToDoNotContact(login, id, reason string) {
+	doNotContact.Add(state.DoNotContactEntry{Login: login, ID: id, Reason: reason, Added: time.Now()})
+	log.WithFields(log.Fields{"login": login, "id": id}).Info("added to the do-not-contact list")
+}
+
+func saveDoNotContact() {
+	if err := States.SaveDoNotContact(doNotContact); err != nil {
+		log.WithError(err).Fatal("couldn't save the do-not-contact list")
+	}
+}
+
+// contactable tells whether the user isn't on the do-not-contact list
+func contactable(login string, id githubv4.ID) bool {
+	if doNotContact == nil {
+		return true
+	}
+	if _, found := doNotContactLogins.Load(login); found {
+		return false
+	}
+	idString, _ := id.(string)
+	_, found := doNotContact.Lookup(login, idString)
+
+	return !found
+}
@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/cache"
+	"github.com/florinutz/gh-recruiter/notify"
+	"github.com/florinutz/gh-recruiter/state"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	eraseFlagReason = "reason"
+	eraseFlagLog    = "log"
+)
+
+var eraseFlags struct {
+	reason string
+	log    bool
+}
+
+// Retention limits how long personal data is kept
+type Retention struct {
+	Days int `toml:"days" comment:"cached github data and recorded runs older than this many days are deleted, 0 keeping them forever"`
+}
+
+// eraseCmd erases people's data
+var eraseCmd = &cobra.Command{
+	Use:   "erase <login>...",
+	Short: "removes everything known about the given people",
+	Long: `Removes the given people from the cache, the crawl state, the candidate pipeline and the runs,
+logging what was erased. The csv files written by previous crawls are not touched.
+The do-not-contact list is kept, as it's what keeps them from being reported again.`,
+	Example: "gh-recruiter erase someone --reason \"asked by email\"\ngh-recruiter erase --log",
+	PreRun:  preRunDnc,
+	Run:     runErase,
+}
+
+func init() {
+	eraseCmd.Flags().StringVar(&eraseFlags.reason, eraseFlagReason, "erasure request", "why the data is erased")
+	eraseCmd.Flags().BoolVar(&eraseFlags.log, eraseFlagLog, false, "print the audit log of erasures instead")
+
+	rootCmd.AddCommand(eraseCmd)
+}
+
+func runErase(cmd *cobra.Command, args []string) {
+	if eraseFlags.log {
+		printErasures()
+		return
+	}
+	if len(args) == 0 {
+		log.Fatal("no one to erase")
+	}
+
+	// the ids are looked up first, as they make it into the cache too
+	ctx := context.Background()
+	ids := make([]string, len(args))
+	for i, login := range args {
+		ids[i] = lookupID(ctx, login)
+	}
+
+	c := Fetcher.Cache
+	if c == nil {
+		var err error
+		if c, err = cache.NewCache(cacheBucketName, 0); err != nil {
+			log.WithError(err).Fatal("couldn't open the cache")
+		}
+	}
+
+	for i, login := range args {
+		erasure := state.Erasure{Subject: login, Reason: eraseFlags.reason, At: time.Now()}
+
+		var err error
+		if erasure.CacheEntries, err = c.Erase([]string{login}, []string{ids[i]}); err != nil {
+			log.WithError(err).WithField("login", login).Fatal("couldn't erase the cache")
+		}
+		if erasure.StateKeys, err = States.Erase(login); err != nil {
+			log.WithError(err).WithField("login", login).Fatal("couldn't erase the crawl state")
+		}
+		if erasure.Files, err = eraseFiles(login); err != nil {
+			log.WithError(err).WithField("login", login).Fatal("couldn't erase the files")
+		}
+
+		logErasure(erasure)
+	}
+}
+
+// applyRetention prunes the cached data and the recorded runs older than the configured retention
+func applyRetention(c *cache.Cache, s *state.Store, retention Retention) {
+	if retention.Days <= 0 {
+		return
+	}
+	maxAge := time.Duration(retention.Days) * 24 * time.Hour
+
+	erasure := state.Erasure{Reason: "retention policy", At: time.Now()}
+	if c != nil {
+		pruned, err := c.Prune(maxAge)
+		if err != nil {
+			log.WithError(err).Warn("couldn't apply the retention policy to the cache")
+		}
+		erasure.CacheEntries = pruned
+	}
+	if s != nil {
+		pruned, err := s.PruneRuns(erasure.At.Add(-maxAge))
+		if err != nil {
+			log.WithError(err).Warn("couldn't apply the retention policy to the runs")
+		}
+		erasure.StateKeys = pruned
+	}
+
+	files, err := pruneFiles(erasure.At.Add(-maxAge))
+	if err != nil {
+		log.WithError(err).Warn("couldn't apply the retention policy to the files")
+	}
+	erasure.Files = files
+
+	if erasure.CacheEntries > 0 || len(erasure.StateKeys) > 0 || len(erasure.Files) > 0 {
+		logErasure(erasure)
+	}
+}
+
+// eraseFiles removes the login from the exclusions audit and the notifications file,
+// returning the files it was removed from
+func eraseFiles(login string) ([]string, error) {
+	return rewriteFiles(
+		func(row []string) bool { return !strings.EqualFold(row[0], login) },
+		func(f *notify.File) (int, error) { return f.Erase(login) })
+}
+
+// pruneFiles removes the exclusions and the notifications older than the time from their files,
+// returning the files that were pruned
+func pruneFiles(before time.Time) ([]string, error) {
+	return rewriteFiles(
+		func(row []string) bool {
+			at, err := time.Parse(time.RFC3339, row[len(row)-1])
+			return err != nil || !at.Before(before)
+		},
+		func(f *notify.File) (int, error) { return f.Prune(before) })
+}
+
+// rewriteFiles rewrites the configured exclusions audit and notifications file, keeping the audit's rows
+// that keep returns true for and removing the notifications through remove
+func rewriteFiles(keep func(row []string) bool, remove func(f *notify.File) (int, error)) (files []string, err error) {
+	if path := RepoCmdConfig.Exclude.Audit; path != "" {
+		removed, err := rewriteAudit(path, keep)
+		if err != nil {
+			return files, err
+		}
+		if removed > 0 {
+			files = append(files, path)
+		}
+	}
+	if path := RepoCmdConfig.Notify.File; path != "" {
+		removed, err := remove(&notify.File{Path: path})
+		if err != nil {
+			return files, err
+		}
+		if removed > 0 {
+			files = append(files, path)
+		}
+	}
+
+	return files, nil
+}
+
+func logErasure(e state.Erasure) {
+	log.WithFields(log.Fields{
+		"subject": e.Subject,
+		"cache":   e.CacheEntries,
+		"state":   e.StateKeys,
+	}).Info("erased")
+
+	if States == nil {
+		log.Warn("no state store, the erasure wasn't logged")
+		return
+	}
+	if err := States.LogErasure(e); err != nil {
+		log.WithError(err).Error("couldn't log the erasure")
+	}
+}
+
+func printErasures() {
+	erasures, err := States.LoadErasures()
+	if err != nil {
+		log.WithError(err).Fatal("couldn't load the erasures")
+	}
+
+	out := mustStdout(state.ErasureCsvHeader)
+	for _, e := range erasures {
+		out.Write(e)
+	}
+	if err = out.Flush(); err != nil {
+		log.WithError(err).Fatal("couldn't print the erasures")
+	}
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/filter"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// Exclusions lists the people that shouldn't be reported, like colleagues or the employees of friendly companies
+type Exclusions struct {
+	Orgs      []string `toml:"orgs" comment:"logins of the github organizations whose public members are excluded"`
+	Companies []string `toml:"companies" comment:"case insensitive regular expressions matched against the users' company"`
+	Audit     string   `toml:"audit" commented:"true" comment:"csv the excluded users are appended to, stderr if missing" omitempty:"true"`
+}
+
+// exclusionAuditHeader is the header of the excluded users' audit csv
+var exclusionAuditHeader = []string{"Login", "Name", "Company", "Organisations", "Reason", "Excluded at"}
+
+// auditCsv opens the audit csv for appending, so that it keeps the previous runs' exclusions.
+// The header is only written to new files.
+func auditCsv(path string) *csv.Writer {
+	if info, err := os.Stat(path); err == nil && info.Size() > 0 {
+		return MustInitCsv(path, nil)
+	}
+
+	return MustInitCsv(path, exclusionAuditHeader)
+}
+
+// rewriteAudit keeps only the audit csv's rows that keep returns true for, returning how many were removed
+func rewriteAudit(path string, keep func(row []string) bool) (removed int, err error) {
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	rows, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid audit %s", path)
+	}
+
+	var kept bytes.Buffer
+	w := csv.NewWriter(&kept)
+	for i, row := range rows {
+		if i > 0 && !keep(row) {
+			removed++
+			continue
+		}
+		w.Write(row)
+	}
+	w.Flush()
+	if err = w.Error(); err != nil || removed == 0 {
+		return 0, err
+	}
+
+	return removed, ioutil.WriteFile(path, kept.Bytes(), 0666)
+}
+
+// exclusionFilter builds the configured exclusion filter, returning nil if nobody is excluded
+func exclusionFilter(conf Exclusions) filter.Filter {
+	exclusion, err := filter.NewExclusion(conf.Orgs, conf.Companies)
+	if err != nil {
+		log.WithError(err).Fatal("invalid exclusions")
+	}
+	if exclusion.Empty() {
+		return nil
+	}
+
+	audit := csv.NewWriter(os.Stderr)
+	if conf.Audit != "" {
+		audit = auditCsv(conf.Audit)
+	}
+
+	return filter.Audited{
+		Filter: exclusion,
+		Audit: func(c *candidate.Candidate, reason string) {
+			orgs := make([]string, 0, len(c.User.Organizations.Nodes))
+			for _, org := range c.User.Organizations.Nodes {
+				orgs = append(orgs, string(org.Login))
+			}
+			audit.Write([]string{c.Login(), string(c.User.Name), string(c.User.Company), strings.Join(orgs, ", "),
+				reason, time.Now().Format(time.RFC3339)})
+			audit.Flush()
+		},
+	}
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/export"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var exportFlags struct {
+	profile, out, tag string
+	statuses          []string
+	list              bool
+}
+
+// exportCmd exports the tracked candidates for an applicant tracking system
+var exportCmd = &cobra.Command{
+	Use:   "export [login...]",
+	Short: "exports the tracked candidates in a format an applicant tracking system imports",
+	Long: `Exports the tracked candidates through a named profile mapping them to the target's schema.
+The greenhouse and lever profiles write csv, the vcard one writes vCards. More profiles (or
+overrides of the builtin ones) go in the config's exports section, each column's value being a
+go text/template over the candidate's Login, Name, FirstName, LastName, Email, Location, Company,
+Bio, Profile, Hireable, Followers, Score, Status, Tags, Repos, Roles, TopLanguages, Timezone,
+LastActive and FirstSeen. Without logins, the candidates having the given statuses and tag are exported.`,
+	Example: `gh-recruiter export --profile greenhouse --out candidates.csv
+
+[[exports]]
+  name = "workable"
+  format = "csv"
+  [[exports.columns]]
+    header = "Full name"
+    value = "{{.Name}}"
+  [[exports.columns]]
+    header = "Summary"
+    value = "{{.Roles}} ({{join .TopLanguages \", \"}})"`,
+	PreRun: preRunCandidates,
+	Run:    runExport,
+}
+
+func init() {
+	exportCmd.Flags().StringVarP(&exportFlags.profile, "profile", "p", "", "export profile")
+	exportCmd.Flags().StringVarP(&exportFlags.out, "out", "o", "", "file to export to, stdout if empty")
+	exportCmd.Flags().StringSliceVar(&exportFlags.statuses, "statuses", nil,
+		"statuses of the exported candidates, all if empty")
+	exportCmd.Flags().StringVar(&exportFlags.tag, "tag", "", "only export the candidates having this tag")
+	exportCmd.Flags().BoolVar(&exportFlags.list, "list", false, "list the available profiles")
+
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExport(cmd *cobra.Command, args []string) {
+	if exportFlags.list {
+		for _, name := range export.Names(RepoCmdConfig.Exports) {
+			fmt.Println(name)
+		}
+		return
+	}
+
+	profile, ok := export.Find(exportFlags.profile, RepoCmdConfig.Exports)
+	if !ok {
+		log.Fatalf("unknown export profile %q, expecting one of %s", exportFlags.profile,
+			strings.Join(export.Names(RepoCmdConfig.Exports), ", "))
+	}
+	exporter, err := export.New(profile)
+	if err != nil {
+		log.WithError(err).Fatal()
+	}
+
+	var w io.Writer = os.Stdout
+	if exportFlags.out != "" {
+		f, err := os.OpenFile(exportFlags.out, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+		if err != nil {
+			log.WithError(err).Fatal()
+		}
+		defer f.Close()
+		w = f
+	}
+
+	records := exportedRecords(args)
+	if err = exporter.Write(w, records, time.Now()); err != nil {
+		log.WithError(err).Fatal()
+	}
+	if exportFlags.out != "" {
+		log.WithFields(log.Fields{"candidates": len(records), "file": exportFlags.out}).Info("candidates exported")
+	}
+}
+
+// exportedRecords returns the given candidates, or else the ones having the wanted statuses and tag.
+// People on the do-not-contact list are never exported.
+func exportedRecords(logins []string) (records []*candidate.Record) {
+	if len(logins) > 0 {
+		for _, login := range logins {
+			records = append(records, mustFindRecord(login))
+		}
+		return
+	}
+
+	for _, r := range candidateDB.Sorted() {
+		if !contactable(r.Candidate.Login(), r.Candidate.User.ID) {
+			continue
+		}
+		if exportFlags.tag != "" && !r.HasTag(exportFlags.tag) {
+			continue
+		}
+		if len(exportFlags.statuses) == 0 {
+			records = append(records, r)
+			continue
+		}
+		for _, status := range exportFlags.statuses {
+			if strings.EqualFold(status, string(r.Status)) {
+				records = append(records, r)
+				break
+			}
+		}
+	}
+
+	return
+}
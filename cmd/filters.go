@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/fetch"
+	"github.com/florinutz/gh-recruiter/filter"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	filterFlagLanguages       = "languages"
+	filterFlagRequireLanguage = "require-language"
+	filterFlagLanguageTop     = "language-top"
+	filterFlagTzFallback      = "timezone-fallback"
+	filterFlagTimezones       = "timezones"
+	filterFlagTzConfidence    = "timezone-confidence"
+	filterFlagActiveWithin    = "active-within"
+	filterFlagMinContribs     = "min-contributions"
+	filterFlagMinScore        = "min-score"
+)
+
+// filterFlags holds the flags tuning which users become candidates
+var filterFlags struct {
+	languages       bool
+	requireLanguage string
+	languageTop     int
+	tzFallback      bool
+	timezones       []int
+	tzConfidence    float64
+	activeWithin    time.Duration
+	minContribs     int
+	minScore        float64
+}
+
+// prCommitTimes holds the authored dates of the PR commits seen while crawling, by login
+var prCommitTimes = map[string][]time.Time{}
+
+// candidateFound, if set, is called for every user that passes the filters
+var candidateFound func(c *candidate.Candidate)
+
+// locationKeywords are the locations the candidates have to be in, nil leaving the location filter out
+var locationKeywords = filter.DefaultLocations
+
+// candidateFilters are the filters every user has to pass in order to be reported
+var candidateFilters filter.Chain
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&filterFlags.languages, filterFlagLanguages, false,
+		"fetch the candidates' language profiles")
+	rootCmd.PersistentFlags().StringVar(&filterFlags.requireLanguage, filterFlagRequireLanguage, "",
+		"only report candidates having this language among their top ones, e.g. go")
+	rootCmd.PersistentFlags().IntVar(&filterFlags.languageTop, filterFlagLanguageTop, candidate.TopLanguagesCount,
+		"how many of the candidates' top languages --require-language looks at")
+	rootCmd.PersistentFlags().BoolVar(&filterFlags.tzFallback, filterFlagTzFallback, false,
+		"for users without a location, infer their timezone from their commits and match it instead")
+	rootCmd.PersistentFlags().IntSliceVar(&filterFlags.timezones, filterFlagTimezones, []int{1, 2},
+		"UTC offsets, in hours, the inferred timezones are matched against")
+	rootCmd.PersistentFlags().Float64Var(&filterFlags.tzConfidence, filterFlagTzConfidence, 0.5,
+		"minimum confidence, between 0 and 1, of the inferred timezones")
+	rootCmd.PersistentFlags().DurationVar(&filterFlags.activeWithin, filterFlagActiveWithin, 0,
+		"only report candidates active within this duration (e.g. 4380h for half a year)")
+	rootCmd.PersistentFlags().IntVar(&filterFlags.minContribs, filterFlagMinContribs, 0,
+		"only report candidates having at least this many contributions over the last year")
+	rootCmd.PersistentFlags().Float64Var(&filterFlags.minScore, filterFlagMinScore, 0,
+		"only report candidates scoring at least this much")
+}
+
+// buildFilters sets up the filters, cheapest first, once the fetcher is ready
+func buildFilters() filter.Chain {
+	var chain filter.Chain
+	if doNotContact != nil {
+		chain = append(chain, filter.Audited{
+			Filter: filter.DoNotContact{List: doNotContact},
+			Audit:  func(c *candidate.Candidate, reason string) { doNotContactLogins.Store(c.Login(), true) },
+		})
+	}
+
+	if locationKeywords != nil {
+		location := filter.Location{Keywords: locationKeywords}
+		if filterFlags.tzFallback {
+			location.CommitTimes = commitTimes
+			location.Offsets = filterFlags.timezones
+			location.MinConfidence = filterFlags.tzConfidence
+		}
+		chain = append(chain, location)
+	}
+
+	if exclusion := exclusionFilter(RepoCmdConfig.Exclude); exclusion != nil {
+		chain = append(chain, exclusion)
+	}
+
+	if filterFlags.activeWithin > 0 || filterFlags.minContribs > 0 {
+		chain = append(chain, filter.Activity{
+			Fetch:            Fetcher.GetActivity,
+			Within:           filterFlags.activeWithin,
+			MinContributions: filterFlags.minContribs,
+			Now:              time.Now,
+		})
+	}
+	if filterFlags.minScore > 0 {
+		chain = append(chain, filter.MinScore{Score: filterFlags.minScore, Now: time.Now})
+	}
+
+	if filterFlags.languages || filterFlags.requireLanguage != "" {
+		chain = append(chain, filter.Languages{
+			Fetch:   Fetcher.GetLanguageProfile,
+			Require: filterFlags.requireLanguage,
+			Top:     filterFlags.languageTop,
+		})
+	}
+
+	return chain
+}
+
+// commitTimes returns the authored dates of the candidate's commits, the ones seen in PRs included
+func commitTimes(ctx context.Context, c *candidate.Candidate) ([]time.Time, error) {
+	times, err := Fetcher.GetCommitTimes(ctx, c.Login(), c.User.ID)
+	if err != nil && len(prCommitTimes[c.Login()]) == 0 {
+		return nil, err
+	}
+
+	return append(times, prCommitTimes[c.Login()]...), nil
+}
+
+// acceptUser runs the user through the filters, returning them as a candidate if they pass
+func acceptUser(ctx context.Context, user fetch.User, interactions ...candidate.Interaction) (
+	*candidate.Candidate, bool) {
+	c := &candidate.Candidate{User: user, Interactions: interactions}
+
+	ok, reason := candidateFilters.Match(ctx, c)
+	if !ok && rootConfig.verbose {
+		fmt.Fprintf(os.Stderr, "%s skipped: %s\n", user.Login, reason)
+	}
+	if ok {
+		trackCandidate(c)
+		recordCandidate(c)
+		if candidateFound != nil {
+			candidateFound(c)
+		}
+	}
+
+	return c, ok
+}
+
+// handleFetchedUser reports the fetched user if they pass the filters, returning them as a candidate
+func handleFetchedUser(ctx context.Context, fetched fetch.UserFetchResult, csvWriter *csv.Writer,
+	interactions ...candidate.Interaction) *candidate.Candidate {
+	if fetched.Err != nil {
+		log.WithError(fetched.Err).Warn()
+		return nil
+	}
+
+	c, ok := acceptUser(ctx, fetched.User, interactions...)
+	if !ok {
+		return nil
+	}
+
+	if err := stdout.Write(c); err != nil {
+		log.WithError(err).Warn("couldn't print candidate")
+	}
+	if csvWriter != nil {
+		csvWriter.Write(c.FormatForCsv())
+		csvWriter.Flush()
+	}
+
+	return c
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/graph"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	graphFlagGraphML = "graphml"
+	graphFlagDot     = "dot"
+	graphFlagJSON    = "json"
+)
+
+var graphFlags struct {
+	graphML, dot, json string
+}
+
+// graphCmd exports the collaboration graph of the repos' PRs
+var graphCmd = &cobra.Command{
+	Use:   "graph [owner/name...]",
+	Short: "builds the who-reviewed-whom graph of the repos' PRs",
+	Long: `Analyzes the PRs of the repos given as arguments (or configured in the repos section
+of the config when there are none), links the PR authors to their reviewers and commenters
+and exports the weighted graph. The candidates are printed with their degree and PageRank.`,
+	Example: "gh-recruiter graph hashicorp/hcl zclconf/go-cty --graphml collab.graphml --dot collab.dot",
+	PreRun:  preRunCrawl,
+	PostRun: postRunCrawl,
+	Run:     runGraph,
+}
+
+func init() {
+	addCrawlFlags(graphCmd)
+	addXlsxFlag(graphCmd)
+
+	graphCmd.Flags().StringVar(&graphFlags.graphML, graphFlagGraphML, "", "GraphML output file")
+	graphCmd.Flags().StringVar(&graphFlags.dot, graphFlagDot, "", "graphviz dot output file")
+	graphCmd.Flags().StringVar(&graphFlags.json, graphFlagJSON, "", "json output file")
+
+	rootCmd.AddCommand(graphCmd)
+}
+
+func runGraph(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	collaboration := graph.New()
+	candidates := candidate.NewSet()
+	for _, r := range reposFromArgs(args) {
+		r.PRs = true // the graph comes from the PRs
+		log.WithField("repo", r.NameWithOwner()).Info("analyzing")
+		r.mustAnalyze(ctx)
+		collaboration.Merge(r.graph)
+		candidates.Merge(r.candidates)
+	}
+	for _, login := range collaboration.Nodes() {
+		if !contactable(login, nil) {
+			collaboration.Remove(login)
+		}
+	}
+	log.WithFields(log.Fields{
+		"users": len(collaboration.Nodes()),
+		"edges": len(collaboration.Edges()),
+	}).Info("graph built")
+
+	writeGraph(graphFlags.graphML, collaboration.WriteGraphML)
+	writeGraph(graphFlags.dot, collaboration.WriteDOT)
+	writeGraph(graphFlags.json, collaboration.WriteJSON)
+
+	metrics := collaboration.Metrics()
+	out := mustStdout(candidate.CsvHeader)
+	for _, c := range candidates.All() {
+		if m, ok := metrics[c.Login()]; ok {
+			c.Centrality = &m
+		}
+		out.Write(c)
+	}
+	if err := out.Flush(); err != nil {
+		log.WithError(err).Fatal("couldn't print the candidates")
+	}
+}
+
+func writeGraph(path string, write func(w io.Writer) error) {
+	if path == "" {
+		return
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		log.WithError(err).Fatal()
+	}
+	defer f.Close()
+
+	if err = write(f); err != nil {
+		log.WithError(err).WithField("file", path).Fatal("couldn't export the graph")
+	}
+	log.WithField("file", path).Info("graph written")
+}
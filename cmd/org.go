@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/fetch"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	orgFlagLanguage     = "language"
+	orgFlagMinStars     = "min-stars"
+	orgFlagArchived     = "archived"
+	orgFlagForks        = "forks"
+	orgFlagPushedWithin = "pushed-within"
+)
+
+// orgFilter selects which of the organization's repos get analyzed
+var orgFilter fetch.RepoFilter
+
+// orgCmd analyzes all the repos of an organization
+var orgCmd = &cobra.Command{
+	Use:     "org <login>",
+	Short:   "filters users who interacted with any of the organization's public repos",
+	PreRun:  preRunCrawl,
+	PostRun: postRunCrawl,
+	Run:     runOrg,
+	Args:    cobra.ExactArgs(1),
+}
+
+func init() {
+	addCrawlFlags(orgCmd)
+	addXlsxFlag(orgCmd)
+
+	orgCmd.Flags().StringVarP(&orgFilter.Language, orgFlagLanguage, "l", "",
+		"only repos having this primary language")
+	orgCmd.Flags().IntVar(&orgFilter.MinStars, orgFlagMinStars, 0,
+		"only repos having at least this many stars")
+	orgCmd.Flags().BoolVar(&orgFilter.Archived, orgFlagArchived, false,
+		"include archived repos")
+	orgCmd.Flags().BoolVar(&orgFilter.Forks, orgFlagForks, false,
+		"include repos that are forks")
+	orgCmd.Flags().DurationVar(&orgFilter.PushedWithin, orgFlagPushedWithin, 0,
+		"only repos pushed to within this duration (e.g. 720h)")
+
+	rootCmd.AddCommand(orgCmd)
+}
+
+func runOrg(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+	login := args[0]
+
+	repos, err := Fetcher.GetOrgRepos(ctx, login, fetch.NewPaginator(nil), orgFilter)
+	if err != nil {
+		log.WithError(err).WithField("org", login).Fatal("couldn't list the organization's repos")
+	}
+	log.WithField("repos", len(repos)).Infof("analyzing %s's repos", login)
+
+	candidates := analyzeRepos(ctx, repos)
+	printCandidates(candidates)
+
+	if RepoCmdConfig.Csv != "" {
+		writeCandidatesCsv(fmt.Sprintf("%s_%s_candidates.csv", RepoCmdConfig.Csv, login), candidates)
+	}
+}
+
+// analyzeRepos runs the configured analyses on each of the repos and merges their candidates
+func analyzeRepos(ctx context.Context, repos []fetch.Repo) *candidate.Set {
+	merged := candidate.NewSet()
+	for _, summary := range repos {
+		r := configuredRepo(string(summary.Owner.Login), string(summary.Name))
+		log.WithField("repo", r.NameWithOwner()).Info("analyzing")
+		r.mustAnalyze(ctx)
+		if err := r.reportNewCandidates(); err != nil {
+			log.WithError(err).Fatal("couldn't report the new candidates")
+		}
+		merged.Merge(r.candidates)
+	}
+
+	return merged
+}
+
+// printCandidates lists the candidates, the best scored first
+func printCandidates(candidates *candidate.Set) {
+	all := candidates.ByScore(time.Now())
+
+	fmt.Printf("\n%d candidates:\n", len(all))
+	for _, c := range all {
+		fmt.Printf("%s (%s, score %.2f): %s\n", c.Login(), c.User.Location, c.Score(time.Now()), c.Summary())
+	}
+}
+
+func writeCandidatesCsv(path string, candidates *candidate.Set) {
+	writer := MustInitCsv(path, candidate.CsvHeader)
+	for _, c := range candidates.All() {
+		writer.Write(c.FormatForCsv())
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		log.WithError(err).Fatal("couldn't write candidates")
+	}
+	log.WithField("file", path).Info("candidates written")
+}
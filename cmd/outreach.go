@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/outreach"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	outreachFlagTemplate = "template"
+	outreachFlagAs       = "as"
+	outreachFlagOut      = "out"
+	outreachFlagFrom     = "from"
+	outreachFlagStatuses = "statuses"
+	outreachFlagTag      = "tag"
+)
+
+var outreachFlags struct {
+	template, as, out, from, tag string
+	statuses                     []string
+}
+
+// outreachCmd drafts messages to the candidates
+var outreachCmd = &cobra.Command{
+	Use:   "outreach [login...]",
+	Short: "drafts personalized messages to the tracked candidates, for review",
+	Long: `Renders a go text/template into one draft per candidate, written as .eml files
+(which mail clients open as unsent messages) or as markdown. Nothing is ever sent.
+
+The template gets the candidate's Login, Name, FirstName, Email, Location, Company, Profile,
+Repo (the one they were most active in), Repos, Roles and their Reviews, Comments and Commits,
+each having a Repo and an URL. The subject can be set with {{define "subject"}}...{{end}}.
+Without logins, the candidates having the given statuses and tag get drafts.`,
+	Example: `gh-recruiter outreach --template msg.tmpl --as md --out drafts
+
+msg.tmpl:
+  {{define "subject"}}Your reviews on {{.Repo}}{{end}}
+  Hi {{.FirstName}},
+
+  I really liked your review at {{(index .Reviews 0).URL}}...`,
+	PreRun: preRunCandidates,
+	Run:    runOutreach,
+}
+
+func init() {
+	outreachCmd.Flags().StringVar(&outreachFlags.template, outreachFlagTemplate, "", "draft template file")
+	outreachCmd.MarkFlagRequired(outreachFlagTemplate)
+	outreachCmd.Flags().StringVar(&outreachFlags.as, outreachFlagAs, outreach.FormatEML,
+		"draft format, eml or md")
+	outreachCmd.Flags().StringVar(&outreachFlags.out, outreachFlagOut, "drafts", "directory the drafts are written to")
+	outreachCmd.Flags().StringVar(&outreachFlags.from, outreachFlagFrom, "", "the drafts' From address")
+	outreachCmd.Flags().StringSliceVar(&outreachFlags.statuses, outreachFlagStatuses, []string{string(candidate.New)},
+		"statuses of the candidates getting drafts")
+	outreachCmd.Flags().StringVar(&outreachFlags.tag, outreachFlagTag, "", "only draft for the candidates having this tag")
+
+	rootCmd.AddCommand(outreachCmd)
+}
+
+func runOutreach(cmd *cobra.Command, args []string) {
+	if outreachFlags.as != outreach.FormatEML && outreachFlags.as != outreach.FormatMarkdown {
+		log.Fatalf("unknown draft format %s, expecting %s or %s", outreachFlags.as, outreach.FormatEML,
+			outreach.FormatMarkdown)
+	}
+
+	text, err := ioutil.ReadFile(outreachFlags.template)
+	if err != nil {
+		log.WithError(err).Fatal("couldn't read the template")
+	}
+	tmpl, err := outreach.Parse(filepath.Base(outreachFlags.template), string(text))
+	if err != nil {
+		log.WithError(err).Fatal()
+	}
+	if err = os.MkdirAll(outreachFlags.out, 0700); err != nil {
+		log.WithError(err).Fatal()
+	}
+
+	written := 0
+	for _, r := range outreachRecipients(args) {
+		draft, err := outreach.Render(tmpl, &r.Candidate)
+		if err != nil {
+			log.WithError(err).Error()
+			continue
+		}
+
+		var content []byte
+		if outreachFlags.as == outreach.FormatEML {
+			content = draft.EML(outreachFlags.from, time.Now())
+		} else {
+			content = draft.Markdown()
+		}
+		path := filepath.Join(outreachFlags.out, draft.Login+"."+outreachFlags.as)
+		if err = ioutil.WriteFile(path, content, 0600); err != nil {
+			log.WithError(err).Fatal()
+		}
+		if draft.To == "" {
+			log.WithField("login", draft.Login).Warn("no public email, the draft has no recipient")
+		}
+		written++
+	}
+
+	log.WithFields(log.Fields{"drafts": written, "dir": outreachFlags.out}).Info("drafts written, review them before sending")
+}
+
+// outreachRecipients returns the candidates to draft for: the given logins, or else the ones
+// having the wanted statuses and tag. People on the do-not-contact list never get drafts.
+func outreachRecipients(logins []string) (recipients []*candidate.Record) {
+	if len(logins) > 0 {
+		for _, login := range logins {
+			recipients = append(recipients, mustFindRecord(login))
+		}
+		return
+	}
+
+	for _, r := range candidateDB.Sorted() {
+		if !contactable(r.Candidate.Login(), r.Candidate.User.ID) {
+			continue
+		}
+		if outreachFlags.tag != "" && !r.HasTag(outreachFlags.tag) {
+			continue
+		}
+		for _, status := range outreachFlags.statuses {
+			if strings.EqualFold(status, string(r.Status)) {
+				recipients = append(recipients, r)
+				break
+			}
+		}
+	}
+
+	return
+}
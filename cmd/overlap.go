@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const overlapFlagMinRepos = "min-repos"
+
+var overlapFlags struct {
+	minRepos int
+}
+
+// overlapCmd finds the users active in several repos at once
+var overlapCmd = &cobra.Command{
+	Use:   "overlap [owner/name...]",
+	Short: "finds users who interacted with several of the repos",
+	Long: `Analyzes each of the repos given as arguments (or configured in the repos section
+of the config when there are none) and reports the users found in at least --min-repos of them.`,
+	Example: "gh-recruiter overlap hashicorp/hcl zclconf/go-cty --prs --forkers",
+	PreRun:  preRunCrawl,
+	PostRun: postRunCrawl,
+	Run:     runOverlap,
+}
+
+func init() {
+	addCrawlFlags(overlapCmd)
+	addXlsxFlag(overlapCmd)
+
+	overlapCmd.Flags().IntVarP(&overlapFlags.minRepos, overlapFlagMinRepos, "k", 2,
+		"minimum number of repos a user has to be active in")
+
+	rootCmd.AddCommand(overlapCmd)
+}
+
+func runOverlap(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	repos := reposFromArgs(args)
+	if len(repos) < overlapFlags.minRepos {
+		log.Fatalf("%d repos can't have users in common in %d of them", len(repos), overlapFlags.minRepos)
+	}
+
+	merged := candidate.NewSet()
+	for _, r := range repos {
+		if !r.Forkers && !r.PRs && !r.Stargazers {
+			log.WithField("repo", r.NameWithOwner()).Warn("nothing to analyze, use --forkers, --prs or --stargazers")
+			continue
+		}
+		log.WithField("repo", r.NameWithOwner()).Info("analyzing")
+		r.mustAnalyze(ctx)
+		merged.Merge(r.candidates)
+	}
+
+	overlapping := merged.InRepos(overlapFlags.minRepos)
+	log.Infof("%d users are active in at least %d of the %d repos", len(overlapping), overlapFlags.minRepos, len(repos))
+
+	out := mustStdout(candidate.CsvHeader)
+	for _, c := range overlapping {
+		out.Write(c)
+	}
+	if err := out.Flush(); err != nil {
+		log.WithError(err).Fatal("couldn't print the overlapping users")
+	}
+}
+
+// reposFromArgs returns the repos given as owner/name arguments, or the configured ones
+func reposFromArgs(args []string) (repos []*repo) {
+	if len(args) == 0 {
+		for _, configured := range RepoCmdConfig.Repos {
+			repos = append(repos, configuredRepo(configured.Owner, configured.Name))
+		}
+		return
+	}
+
+	for _, arg := range args {
+		parts := strings.Split(arg, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			log.WithField("repo", arg).Fatal("repos should be given as owner/name")
+		}
+		repos = append(repos, newRepo(parts[0], parts[1], RepoCmdConfig.RepoSettings))
+	}
+
+	return
+}
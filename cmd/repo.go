@@ -5,13 +5,18 @@ import (
 	"encoding/csv"
 	"fmt"
 	"os"
-	"strings"
 	"time"
 
 	"github.com/spf13/viper"
 
 	"github.com/florinutz/gh-recruiter/cache"
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/export"
 	"github.com/florinutz/gh-recruiter/fetch"
+	"github.com/florinutz/gh-recruiter/graph"
+	"github.com/florinutz/gh-recruiter/output"
+	"github.com/florinutz/gh-recruiter/state"
+	"github.com/pkg/errors"
 	"github.com/shurcooL/githubv4"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -20,47 +25,135 @@ import (
 
 const (
 	cacheBucketName = "gh-recruiter"
+	stateDirName    = "gh-recruiter-state"
 
 	repoFlagCsvOutput = "output"
 	repoFlagForkers   = "forkers"
 	repoFlagPrs       = "prs"
 	repoFlagRepos     = "repo"
+	repoFlagResume    = "resume"
+	repoFlagStars     = "stargazers"
+	repoFlagIncr      = "incremental"
+	repoFlagXlsx      = "xlsx"
 )
 
 type RepoSettings struct {
-	Tokens  []string `toml:"tokens" commented:"false" comment:"Pool of github token to be used randomly. \n Supplying tokens via the GR_TOKEN env var will take precedence over this."`
-	Csv     string   `toml:"csv" commented:"true" comment:"if this is present, csv will pe outputted at the desired path" omitempty:"true"`
-	Verbose bool     `toml:"verbose" comment:"too much output will be shown, but some might enjoy this" omitempty:"true"`
-	Forkers bool     `toml:"forkers" comment:"analyze forkers" omitempty:"true"`
-	PRs     bool     `toml:"prs" commented:"true" comment:"analyze PRs" omitempty:"true"`
+	Tokens     []string `toml:"tokens" commented:"false" comment:"Pool of github token to be used randomly. \n Supplying tokens via the GR_TOKEN env var will take precedence over this."`
+	Csv        string   `toml:"csv" commented:"true" comment:"if this is present, csv will pe outputted at the desired path" omitempty:"true"`
+	Verbose    bool     `toml:"verbose" comment:"too much output will be shown, but some might enjoy this" omitempty:"true"`
+	Forkers    bool     `toml:"forkers" comment:"analyze forkers" omitempty:"true"`
+	PRs        bool     `toml:"prs" commented:"true" comment:"analyze PRs" omitempty:"true"`
+	Stargazers bool     `toml:"stargazers" commented:"true" comment:"analyze stargazers" omitempty:"true"`
+	// Incremental only fetches what happened since the previous run
+	Incremental bool `toml:"incremental" commented:"true" comment:"only fetch activity newer than the last run's" omitempty:"true"`
 }
 
 // repo represents the settings for individual repos
 type repo struct {
-	Owner string `toml:"owner" comment:"repo owner" omitempty:"false"`
-	Name  string `toml:"name" comment:"repo owner" omitempty:"false"`
-	RepoSettings
+	Owner        string `toml:"owner" comment:"repo owner" omitempty:"false"`
+	Name         string `toml:"name" comment:"repo owner" omitempty:"false"`
+	RepoSettings `mapstructure:",squash"`
+
+	// marks are the repo's high-water marks, loaded only for incremental runs
+	marks *state.Marks
+	// newCandidates are the interesting users that previous runs haven't reported
+	newCandidates *candidate.Set
+	// candidates collects the interesting users together with their interactions
+	candidates *candidate.Set
+	// interactions holds the interactions that have urls (comments, reviews), by login
+	interactions map[string][]candidate.Interaction
+	// graph links the PR authors to their reviewers and commenters
+	graph *graph.Graph
+}
+
+// newRepo returns a repo to be analyzed with the given settings
+func newRepo(owner, name string, settings RepoSettings) *repo {
+	return &repo{
+		Owner:         owner,
+		Name:          name,
+		RepoSettings:  settings,
+		candidates:    candidate.NewSet(),
+		newCandidates: candidate.NewSet(),
+		interactions:  map[string][]candidate.Interaction{},
+		graph:         graph.New(),
+	}
+}
+
+// repoSwitches are the boolean settings of a repo section, nil when the section leaves them to the global ones
+type repoSwitches struct {
+	Owner       string
+	Name        string
+	Verbose     *bool
+	Forkers     *bool
+	PRs         *bool
+	Stargazers  *bool
+	Incremental *bool
+}
+
+// mergeSettings lays a repo's own settings over the global ones
+func mergeSettings(global, own RepoSettings, switches repoSwitches) RepoSettings {
+	merged := global
+	if len(own.Tokens) > 0 {
+		merged.Tokens = own.Tokens
+	}
+	if own.Csv != "" {
+		merged.Csv = own.Csv
+	}
+	for _, s := range []struct{ merged, own *bool }{
+		{&merged.Verbose, switches.Verbose},
+		{&merged.Forkers, switches.Forkers},
+		{&merged.PRs, switches.PRs},
+		{&merged.Stargazers, switches.Stargazers},
+		{&merged.Incremental, switches.Incremental},
+	} {
+		if s.own != nil {
+			*s.merged = *s.own
+		}
+	}
+
+	return merged
+}
+
+// NameWithOwner returns the repo's owner/name
+func (r *repo) NameWithOwner() string {
+	return r.Owner + "/" + r.Name
 }
 
 // RepoConfig represents configs for this command
 type RepoConfig struct {
-	RepoSettings `toml:"global" comment:"global settings that will be overridden by individual repo settings"`
-	Repos        []*repo `toml:"repos" comment:"each repository can overwrite the base settings"`
+	RepoSettings `toml:"global" mapstructure:"global" comment:"global settings that will be overridden by individual repo settings"`
+	Repos        []*repo          `toml:"repos" comment:"each repository can overwrite the base settings"`
+	Exclude      Exclusions       `toml:"exclude" comment:"users that are never reported"`
+	Retention    Retention        `toml:"retention" comment:"how long personal data is kept"`
+	Notify       NotifyConfig     `toml:"notify" comment:"where the notifications go, besides stdout"`
+	Daemon       DaemonConfig     `toml:"daemon" comment:"the crawls run by the daemon"`
+	Serve        ServeConfig      `toml:"serve" comment:"the http api"`
+	Exports      []export.Profile `toml:"exports" comment:"export profiles, besides the builtin greenhouse, lever and vcard ones"`
+	Xlsx         string           `toml:"xlsx" commented:"true" comment:"if this is present, the crawls' candidates are written to this excel workbook"`
 }
 
 // RepoCmdConfig covers all config options for this command
 var (
 	RepoCmdConfig RepoConfig
 	Fetcher       fetch.GithubFetcher
+	States        *state.Store
+	// stdout prints the interesting users as they're found
+	stdout output.Writer
 )
 
+// repoFlags holds the flags that are not part of the config
+var repoFlags struct {
+	resume bool
+}
+
 // repoCmd represents the repo command
 var repoCmd = &cobra.Command{
-	Use:    "repo",
-	Short:  "filters users who interacted with the repo by location",
-	PreRun: preRunRepo,
-	Run:    runRepo,
-	Args:   cobra.ExactArgs(2),
+	Use:     "repo",
+	Short:   "filters users who interacted with the repo by location",
+	PreRun:  preRunCrawl,
+	PostRun: postRunCrawl,
+	Run:     runRepo,
+	Args:    cobra.ExactArgs(2),
 }
 
 func init() {
@@ -68,83 +161,512 @@ func init() {
 		veep = viper.New()
 	}
 
-	repoCmd.Flags().StringVarP(&RepoCmdConfig.Csv, repoFlagCsvOutput, "o", "",
+	addCrawlFlags(repoCmd)
+	addXlsxFlag(repoCmd)
+
+	veep.BindEnv("token")
+
+	rootCmd.AddCommand(repoCmd)
+}
+
+// addCrawlFlags adds the flags that pick what to analyze in a repo
+func addCrawlFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&RepoCmdConfig.Csv, repoFlagCsvOutput, "o", "",
 		"Csv output file")
-	repoCmd.Flags().BoolVarP(&RepoCmdConfig.Forkers, repoFlagForkers, "f", false,
+	cmd.Flags().BoolVarP(&RepoCmdConfig.Forkers, repoFlagForkers, "f", false,
 		"fetch forkers?")
-	repoCmd.Flags().BoolVarP(&RepoCmdConfig.PRs, repoFlagPrs, "p", false,
+	cmd.Flags().BoolVarP(&RepoCmdConfig.PRs, repoFlagPrs, "p", false,
 		"fetch users involved in prs?")
+	cmd.Flags().BoolVarP(&RepoCmdConfig.Stargazers, repoFlagStars, "s", false,
+		"fetch stargazers?")
+	cmd.Flags().BoolVarP(&RepoCmdConfig.Incremental, repoFlagIncr, "i", false,
+		"only fetch activity that happened since the last run")
+	cmd.Flags().BoolVar(&repoFlags.resume, repoFlagResume, false,
+		"resume the crawl from where the last failed one stopped")
+}
 
-	veep.BindEnv("token")
-
-	if err := veep.BindPFlag("csv", repoCmd.Flag(repoFlagCsvOutput)); err != nil {
-		log.WithError(err).Fatal("config binding error")
-	}
-	if err := veep.BindPFlag("forkers", repoCmd.Flag(repoFlagForkers)); err != nil {
-		log.WithError(err).Fatal("config binding error")
-	}
-	if err := veep.BindPFlag("prs", repoCmd.Flag(repoFlagPrs)); err != nil {
-		log.WithError(err).Fatal("config binding error")
+// bindCrawlFlags binds the running command's crawl flags to the global settings, the flags that were set
+// winning over the config
+func bindCrawlFlags(cmd *cobra.Command) {
+	for key, flag := range map[string]string{
+		"csv":         repoFlagCsvOutput,
+		"forkers":     repoFlagForkers,
+		"prs":         repoFlagPrs,
+		"stargazers":  repoFlagStars,
+		"incremental": repoFlagIncr,
+	} {
+		if cmd.Flag(flag) == nil {
+			continue
+		}
+		if err := veep.BindPFlag("global."+key, cmd.Flag(flag)); err != nil {
+			log.WithError(err).Fatal("config binding error")
+		}
 	}
 
-	rootCmd.AddCommand(repoCmd)
+	// the workbook is a top level setting
+	if flag := cmd.Flag(repoFlagXlsx); flag != nil {
+		if err := veep.BindPFlag("xlsx", flag); err != nil {
+			log.WithError(err).Fatal("config binding error")
+		}
+	}
 }
 
 func preRunRepo(cmd *cobra.Command, args []string) {
 	var err error
+	bindCrawlFlags(cmd)
 	s := veep.AllSettings()
 	if err = veep.Unmarshal(&RepoCmdConfig); err != nil {
 		log.WithError(err).WithField("cca", s).Fatal("couldn't parse config")
 	}
 	log.WithField("config", RepoCmdConfig).Debug("fetched config")
 
-	ctx := context.Background()
+	Fetcher = newFetcher(context.Background())
+	stdout = mustStdout(candidate.CsvHeader)
 
+	// without the state store there's no do-not-contact list, so nothing may be reported
+	if States, err = state.NewStore(stateDirName); err != nil {
+		log.WithError(err).Fatal("couldn't open the state store")
+	}
+	if doNotContact, err = States.LoadDoNotContact(); err != nil {
+		log.WithError(err).Fatal("couldn't load the do-not-contact list")
+	}
+	applyRetention(Fetcher.Cache, States, RepoCmdConfig.Retention)
+	loadCandidateDB()
+	candidateFilters = buildFilters()
+}
+
+// newFetcher returns a caching fetcher using the first configured token
+func newFetcher(ctx context.Context) fetch.GithubFetcher {
+	if len(RepoCmdConfig.Tokens) == 0 {
+		log.Fatal("no github token configured")
+	}
 	token := RepoCmdConfig.Tokens[0]
 	ghClient := githubv4.NewClient(oauth2.NewClient(ctx, oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: token})))
 	log.Debugf("Github access token: %s\n", token)
 
-	var c *cache.Cache
-	if c, err = cache.NewCache(cacheBucketName, 168*time.Hour); err != nil {
+	c, err := cache.NewCache(cacheBucketName, 168*time.Hour)
+	if err != nil {
 		log.WithError(err).Warn("running with no cache")
 	} else {
 		log.WithField("cache", c).Debug("got cache")
 	}
 
-	Fetcher = fetch.GithubFetcher{Client: ghClient, Cache: c}
+	return fetch.GithubFetcher{Client: ghClient, Cache: c, Stats: &fetch.Stats{}}
 }
 
 func runRepo(cmd *cobra.Command, args []string) {
-	//ctx := context.Background()
-	// todo merge individual repo settings over the root ones
-	if RepoCmdConfig.Forkers {
-		// DoForkers(ctx, args)
+	ctx := context.Background()
+	r := configuredRepo(args[0], args[1])
+	r.mustAnalyze(ctx)
+	if err := r.reportNewCandidates(); err != nil {
+		log.WithError(err).Fatal("couldn't report the new candidates")
 	}
-	if RepoCmdConfig.PRs {
-		// DoPRs(ctx, args)
+}
+
+// configuredRepo returns the repo with its own configured settings laid over the global ones
+func configuredRepo(owner, name string) *repo {
+	r := newRepo(owner, name, RepoCmdConfig.RepoSettings)
+
+	var switches []repoSwitches
+	if err := veep.UnmarshalKey("repos", &switches); err != nil {
+		log.WithError(err).Warn("couldn't parse the repos' settings")
+	}
+	for i, configured := range RepoCmdConfig.Repos {
+		if configured.Owner != r.Owner || configured.Name != r.Name {
+			continue
+		}
+		var own repoSwitches
+		if i < len(switches) {
+			own = switches[i]
+		}
+		r.RepoSettings = mergeSettings(RepoCmdConfig.RepoSettings, configured.RepoSettings, own)
 	}
+
+	return r
 }
 
-func (r *repo) DoForkers(ctx context.Context, owner, repo string) {
-	logins, err := Fetcher.GetForkers(ctx, owner, repo, (*githubv4.String)(nil), 100)
+// analyze runs the configured analyses on the repo, collecting its candidates.
+// A failed analysis saves its progress and stops the others.
+func (r *repo) analyze(ctx context.Context) error {
+	recordRepo(r.NameWithOwner())
+	r.loadMarks()
+	if r.Forkers {
+		if err := r.DoForkers(ctx); err != nil {
+			return err
+		}
+	}
+	if r.PRs {
+		if err := r.DoPRs(ctx); err != nil {
+			return err
+		}
+	}
+	if r.Stargazers {
+		if err := r.DoStargazers(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mustAnalyze analyzes the repo, exiting if the crawl fails
+func (r *repo) mustAnalyze(ctx context.Context) {
+	if err := r.analyze(ctx); err != nil {
+		log.WithError(err).WithField("repo", r.NameWithOwner()).Fatal("rerun with --resume to continue")
+	}
+}
+
+// loadMarks loads the repo's high-water marks for incremental runs
+func (r *repo) loadMarks() {
+	if !r.Incremental {
+		return
+	}
+	if States == nil {
+		log.Warn("no crawl state available, running a full crawl")
+		return
+	}
+
+	var err error
+	if r.marks, err = States.LoadMarks(r.Owner, r.Name); err != nil {
+		log.WithError(err).Warn("couldn't load the previous run's marks, running a full crawl")
+	} else if !r.marks.LastRun.IsZero() {
+		log.WithField("last run", r.marks.LastRun).Info("only fetching activity since the last run")
+	}
+}
+
+// since returns the mark a connection's crawl should stop at, or the zero time for full crawls
+func (r *repo) since(mark func(m *state.Marks) time.Time) time.Time {
+	if r.marks == nil {
+		return time.Time{}
+	}
+
+	return mark(r.marks)
+}
+
+// advanceMark moves the repo's mark forward after a connection was fully crawled
+func (r *repo) advanceMark(mark func(m *state.Marks) *time.Time, newest time.Time) {
+	if r.marks == nil {
+		return
+	}
+	state.Advance(mark(r.marks), newest)
+	if err := States.SaveMarks(r.marks); err != nil {
+		log.WithError(err).Warn("couldn't save marks")
+	}
+}
+
+// reportNewCandidates shows the interesting users that previous runs didn't report and remembers them
+func (r *repo) reportNewCandidates() error {
+	if r.marks == nil {
+		return nil
+	}
+
+	log.WithField("repo", r.NameWithOwner()).Infof("%d new candidates since last run", r.newCandidates.Len())
+	writer, err := r.csvFor("new", candidate.CsvHeader)
 	if err != nil {
-		log.WithError(err).Fatal()
+		return err
+	}
+	for _, c := range r.newCandidates.All() {
+		stdout.Write(c)
+		if writer != nil {
+			writer.Write(c.FormatForCsv())
+		}
+		r.marks.Seen[c.Login()] = true
 	}
-	var writer *csv.Writer
-	if RepoCmdConfig.Csv != "" {
-		path := fmt.Sprintf("%s_%s-%s_forkers.csv", RepoCmdConfig.Csv, owner, repo)
-		writer = MustInitCsv(path, true)
+	stdout.Flush()
+	if writer != nil {
+		writer.Flush()
 	}
-	Fetcher.GetUsersByLogins(ctx, logins, writer, userFetchedCallback)
+
+	if err := States.SaveMarks(r.marks); err != nil {
+		log.WithError(err).Warn("couldn't save marks")
+	}
+
+	return nil
 }
 
-func (r *repo) DoPRs(ctx context.Context, args []string) {
-	var commenterLogins, reviewerLogins []string
+// loadCheckpoint returns the checkpoint the crawl of the connection should continue from.
+// Unless resuming, the crawl starts from scratch.
+func (r *repo) loadCheckpoint(connection string) *state.Checkpoint {
+	if States == nil || !repoFlags.resume {
+		return state.NewCheckpoint(r.Owner, r.Name, connection)
+	}
 
-	prs, err := Fetcher.GetPRs(ctx, args[0], args[1], (*githubv4.String)(nil), 0)
+	checkpoint, err := States.LoadCheckpoint(r.Owner, r.Name, connection)
 	if err != nil {
-		log.WithError(err).Fatal()
+		log.WithError(err).Warn("couldn't load checkpoint, starting from scratch")
+	} else if checkpoint.EndCursor != "" {
+		log.WithFields(log.Fields{
+			"connection": connection,
+			"cursor":     checkpoint.EndCursor,
+			"resolved":   checkpoint.ResolvedCount(),
+		}).Info("resuming crawl")
+	}
+
+	return checkpoint
+}
+
+func saveCheckpoint(checkpoint *state.Checkpoint) {
+	if States == nil {
+		return
+	}
+	if err := States.SaveCheckpoint(checkpoint); err != nil {
+		log.WithError(err).Warn("couldn't save checkpoint")
+	}
+}
+
+func clearCheckpoint(checkpoint *state.Checkpoint) {
+	if States == nil {
+		return
+	}
+	if err := States.Delete(checkpoint.Key()); err != nil {
+		log.WithError(err).Warn("couldn't clear checkpoint")
+	}
+}
+
+// pagerFor returns a paginator continuing after the checkpoint's cursor
+func pagerFor(checkpoint *state.Checkpoint) *fetch.Paginator {
+	if checkpoint.EndCursor == "" {
+		return fetch.NewPaginator(nil)
+	}
+	cursor := githubv4.String(checkpoint.EndCursor)
+
+	return fetch.NewPaginator(&cursor)
+}
+
+// failCrawl persists the progress made so far, returning the error the crawl failed with
+func failCrawl(err error, checkpoint *state.Checkpoint, pager *fetch.Paginator) error {
+	if pager.After != nil {
+		checkpoint.EndCursor = string(*pager.After)
+	}
+	saveCheckpoint(checkpoint)
+
+	return errors.Wrapf(err, "crawl failed at cursor %q", checkpoint.EndCursor)
+}
+
+// resolveLogins fetches the users behind the not yet resolved logins, recording each in the checkpoint.
+// It returns whether all of them got resolved, the failed and timed out fetches being left for a later run.
+func (r *repo) resolveLogins(ctx context.Context, checkpoint *state.Checkpoint, role candidate.Role, logins []string,
+	writer *csv.Writer) bool {
+	logins = checkpoint.Unresolved(string(role), logins)
+	if len(logins) == 0 {
+		return true
+	}
+
+	Fetcher.GetUsersByLogins(ctx, logins, writer,
+		func(ctx context.Context, fetched fetch.UserFetchResult, writer *csv.Writer) {
+			c := handleFetchedUser(ctx, fetched, writer, r.interactionsOf(fetched.Login, role)...)
+			if c != nil {
+				r.collect(c)
+			}
+			// there's no point in retrying the deleted accounts
+			if fetched.Err == nil || fetch.IsNotFound(fetched.Err) {
+				checkpoint.Resolve(string(role), fetched.Login)
+				saveCheckpoint(checkpoint)
+			}
+		})
+	stdout.Flush()
+
+	unresolved := checkpoint.Unresolved(string(role), logins)
+	if len(unresolved) > 0 {
+		log.WithFields(log.Fields{"role": role, "users": len(unresolved)}).Warn("some users couldn't be fetched")
+	}
+
+	return len(unresolved) == 0
+}
+
+// collect adds the candidate to the repo's candidates, and to the new ones if previous runs didn't report them
+func (r *repo) collect(c *candidate.Candidate) {
+	r.candidates.Put(c)
+	if r.marks != nil && !r.marks.Seen[c.Login()] {
+		r.newCandidates.Put(c)
+	}
+}
+
+// finish advances the repo's mark and clears the checkpoint once all of the connection's users were resolved.
+// Otherwise both are kept, so that --resume or the next incremental run gets the missing users.
+// The mark isn't advanced either when the listing was cut short by the page limit, as it would skip the rest.
+func (r *repo) finish(checkpoint *state.Checkpoint, resolved bool, mark func(m *state.Marks) *time.Time) {
+	if !resolved {
+		log.WithField("connection", checkpoint.Connection).Warn("crawl incomplete, rerun with --resume to continue")
+		return
+	}
+	if checkpoint.Truncated {
+		log.WithField("connection", checkpoint.Connection).Info("listing stopped at the page limit, the mark stays")
+	} else {
+		r.advanceMark(mark, checkpoint.Newest)
+	}
+	clearCheckpoint(checkpoint)
+}
+
+// interactionsOf returns the login's recorded interactions having the role,
+// or a bare interaction if none were recorded
+func (r *repo) interactionsOf(login string, role candidate.Role) (result []candidate.Interaction) {
+	for _, i := range r.interactions[login] {
+		if i.Role == role {
+			result = append(result, i)
+		}
+	}
+	if len(result) == 0 {
+		result = append(result, candidate.Interaction{Repo: r.NameWithOwner(), Role: role})
+	}
+
+	return
+}
+
+// record remembers an interaction that has an url
+func (r *repo) record(login string, role candidate.Role, url string) {
+	r.interactions[login] = append(r.interactions[login],
+		candidate.Interaction{Repo: r.NameWithOwner(), Role: role, URL: url})
+}
+
+// csvFor opens the csv output for the given kind of data, appending to it when resuming a crawl that wrote it.
+// The writer is nil when there's no csv output.
+func (r *repo) csvFor(kind string, header []string) (*csv.Writer, error) {
+	if r.Csv == "" {
+		return nil, nil
+	}
+	path := fmt.Sprintf("%s_%s-%s_%s.csv", r.Csv, r.Owner, r.Name, kind)
+	if _, err := os.Stat(path); repoFlags.resume && err == nil {
+		return initCsv(path, nil)
+	}
+
+	return initCsv(path, header)
+}
+
+// DoForkers analyzes the users who forked the repo
+func (r *repo) DoForkers(ctx context.Context) error {
+	const role = "forkers"
+	checkpoint := r.loadCheckpoint(role)
+
+	if !checkpoint.Listed {
+		pager := pagerFor(checkpoint)
+		since := r.since(func(m *state.Marks) time.Time { return m.ForkCreatedAt })
+		forks, err := Fetcher.GetForkers(ctx, r.Owner, r.Name, pager, 100, since)
+		for _, fork := range forks {
+			checkpoint.Logins[role] = append(checkpoint.Logins[role], fork.Login)
+			state.Advance(&checkpoint.Newest, fork.CreatedAt)
+		}
+		if err != nil {
+			return failCrawl(err, checkpoint, pager)
+		}
+		checkpoint.Listed, checkpoint.Truncated = true, !pager.Exhausted()
+		saveCheckpoint(checkpoint)
+	}
+
+	writer, err := r.csvFor(role, candidate.CsvHeader)
+	if err != nil {
+		return err
+	}
+	resolved := r.resolveLogins(ctx, checkpoint, candidate.Forker, checkpoint.Logins[role], writer)
+	r.finish(checkpoint, resolved, func(m *state.Marks) *time.Time { return &m.ForkCreatedAt })
+
+	return nil
+}
+
+// DoStargazers analyzes the users who starred the repo
+func (r *repo) DoStargazers(ctx context.Context) error {
+	const role = "stargazers"
+	checkpoint := r.loadCheckpoint(role)
+
+	if !checkpoint.Listed {
+		pager := pagerFor(checkpoint)
+		since := r.since(func(m *state.Marks) time.Time { return m.StarStarredAt })
+		stargazers, err := Fetcher.GetStargazers(ctx, r.Owner, r.Name, pager, 100, since)
+		for _, stargazer := range stargazers {
+			checkpoint.Logins[role] = append(checkpoint.Logins[role], stargazer.Login)
+			state.Advance(&checkpoint.Newest, stargazer.StarredAt)
+		}
+		if err != nil {
+			return failCrawl(err, checkpoint, pager)
+		}
+		checkpoint.Listed, checkpoint.Truncated = true, !pager.Exhausted()
+		saveCheckpoint(checkpoint)
+	}
+
+	writer, err := r.csvFor(role, candidate.CsvHeader)
+	if err != nil {
+		return err
+	}
+	resolved := r.resolveLogins(ctx, checkpoint, candidate.Stargazer, checkpoint.Logins[role], writer)
+	r.finish(checkpoint, resolved, func(m *state.Marks) *time.Time { return &m.StarStarredAt })
+
+	return nil
+}
+
+// DoPRs analyzes the users involved in the repo's PRs
+func (r *repo) DoPRs(ctx context.Context) error {
+	const (
+		commenters = "pr_commenters"
+		reviewers  = "pr_reviewers"
+	)
+	checkpoint := r.loadCheckpoint("prs")
+
+	if !checkpoint.Listed {
+		pager := pagerFor(checkpoint)
+		pager.MaxPages = 3
+		since := r.since(func(m *state.Marks) time.Time { return m.PRUpdatedAt })
+		prs, err := Fetcher.GetPRs(ctx, r.Owner, r.Name, pager, since)
+		if printErr := r.printPRs(ctx, prs); printErr != nil {
+			return printErr
+		}
+		for _, pr := range prs {
+			state.Advance(&checkpoint.Newest, pr.UpdatedAt.Time)
+			for _, comment := range pr.Comments.Nodes {
+				checkpoint.Logins[commenters] = append(checkpoint.Logins[commenters], string(comment.Author.Login))
+				checkpoint.Interactions = append(checkpoint.Interactions, state.Interaction{
+					Login: string(comment.Author.Login), Role: candidate.Commenter, URL: comment.URL.String(),
+					Author: string(pr.Author.Login),
+				})
+			}
+			for _, review := range pr.Reviews.Nodes {
+				checkpoint.Logins[reviewers] = append(checkpoint.Logins[reviewers], string(review.Author.Login))
+				checkpoint.Interactions = append(checkpoint.Interactions, state.Interaction{
+					Login: string(review.Author.Login), Role: candidate.Reviewer, URL: review.URL.String(),
+					Author: string(pr.Author.Login),
+				})
+			}
+		}
+		if err != nil {
+			return failCrawl(err, checkpoint, pager)
+		}
+		checkpoint.Listed, checkpoint.Truncated = true, !pager.Exhausted()
+		saveCheckpoint(checkpoint)
+	}
+
+	// the interactions are replayed from the checkpoint, as resumed crawls don't list the PRs again
+	for _, i := range checkpoint.Interactions {
+		r.record(i.Login, i.Role, i.URL)
+		kind := graph.Commented
+		if i.Role == candidate.Reviewer {
+			kind = graph.Reviewed
+		}
+		r.graph.Add(i.Login, i.Author, kind)
+	}
+
+	commentersCsv, err := r.csvFor(commenters, candidate.CsvHeader)
+	if err != nil {
+		return err
+	}
+	reviewersCsv, err := r.csvFor(reviewers, candidate.CsvHeader)
+	if err != nil {
+		return err
+	}
+	resolved := r.resolveLogins(ctx, checkpoint, candidate.Commenter, checkpoint.Logins[commenters], commentersCsv)
+	if !r.resolveLogins(ctx, checkpoint, candidate.Reviewer, checkpoint.Logins[reviewers], reviewersCsv) {
+		resolved = false
+	}
+	r.finish(checkpoint, resolved, func(m *state.Marks) *time.Time { return &m.PRUpdatedAt })
+
+	return nil
+}
+
+// printPRs shows the PRs' interactions and writes their commit authors to csv, collecting the interesting ones.
+// The people who asked not to be contacted are left out.
+func (r *repo) printPRs(ctx context.Context, prs []fetch.PrWithData) error {
+	writer, err := r.csvFor("pr_commits", fetch.UserCsvHeader)
+	if err != nil {
+		return err
 	}
 
 	for _, pr := range prs {
@@ -154,8 +676,10 @@ func (r *repo) DoPRs(ctx context.Context, args []string) {
 		if commentsCount > 0 {
 			fmt.Printf("\n%d comments:\n", commentsCount)
 			for _, comment := range pr.Comments.Nodes {
+				if !contactable(string(comment.Author.Login), nil) {
+					continue
+				}
 				fmt.Printf("%s (%s):\n", comment.Author.Login, comment.URL.String())
-				commenterLogins = append(commenterLogins, string(comment.Author.Login))
 			}
 		}
 
@@ -163,22 +687,20 @@ func (r *repo) DoPRs(ctx context.Context, args []string) {
 		if reviewsCount > 0 {
 			fmt.Printf("\n%d reviews:\n", reviewsCount)
 			for _, review := range pr.Reviews.Nodes {
+				if !contactable(string(review.Author.Login), nil) {
+					continue
+				}
 				fmt.Printf("%s (%s):\n", review.Author.Login, review.URL.String())
-				reviewerLogins = append(reviewerLogins, string(review.Author.Login))
 			}
 		}
 
 		commitsCount := len(pr.Commits.Nodes)
 		if commitsCount > 0 {
 			fmt.Printf("\n%d commits:\n", commitsCount)
-
-			var writer *csv.Writer
-			if RepoCmdConfig.Csv != "" {
-				path := fmt.Sprintf("%s_%s-%s_pr_commits.Csv", RepoCmdConfig.Csv, args[0], args[1])
-				writer = MustInitCsv(path, true)
-			}
-
 			for _, commit := range pr.Commits.Nodes {
+				if author := commit.Commit.Author.User; !contactable(string(author.Login), author.ID) {
+					continue
+				}
 				fmt.Printf("%s (%d additions, %d deletions, url %s):\n",
 					commit.Commit.Author.User.ID,
 					commit.Commit.Additions,
@@ -188,89 +710,58 @@ func (r *repo) DoPRs(ctx context.Context, args []string) {
 				if writer != nil {
 					writer.Write(commit.Commit.Author.User.FormatForCsv())
 				}
+				if author := commit.Commit.Author.User; author.Login != "" {
+					login := string(author.Login)
+					prCommitTimes[login] = append(prCommitTimes[login], commit.Commit.AuthoredDate.Time)
+					c, ok := acceptUser(ctx, author, candidate.Interaction{
+						Repo: r.NameWithOwner(),
+						Role: candidate.Committer,
+						URL:  commit.Commit.URL.String(),
+					})
+					if ok {
+						r.collect(c)
+					}
+				}
 			}
 		}
 	}
-	if len(commenterLogins) > 0 {
-		var writer *csv.Writer
-		if RepoCmdConfig.Csv != "" {
-			path := fmt.Sprintf("%s_%s-%s_pr_commenters.Csv", RepoCmdConfig.Csv, args[0], args[1])
-			writer = MustInitCsv(path, true)
-		}
-		Fetcher.GetUsersByLogins(ctx, commenterLogins, writer, userFetchedCallback)
-	}
-	if len(reviewerLogins) > 0 {
-		var writer *csv.Writer
-		if RepoCmdConfig.Csv != "" {
-			path := fmt.Sprintf("%s_%s-%s_pr_reviewers.Csv", RepoCmdConfig.Csv, args[0], args[1])
-			writer = MustInitCsv(path, true)
-		}
-		Fetcher.GetUsersByLogins(ctx, reviewerLogins, writer, userFetchedCallback)
-	}
-}
 
-func userFetchedCallback(ctx context.Context, fetched fetch.UserFetchResult, csvWriter *csv.Writer) {
-	if fetched.Err != nil {
-		log.WithError(fetched.Err).Warn()
-		return
+	if writer != nil {
+		writer.Flush()
 	}
 
-	if isLocationInteresting(string(fetched.User.Location)) {
-		fmt.Printf("%q\n", fetched.User.FormatForCsv())
-		if csvWriter != nil {
-			csvWriter.Write(fetched.User.FormatForCsv())
-			csvWriter.Flush()
-		}
-	} else if rootConfig.verbose {
-		fmt.Fprintf(os.Stderr, "%s's \"%s\" location was not interesting\n",
-			fetched.Login, fetched.User.Location)
-	}
+	return nil
 }
 
-func isLocationInteresting(location string) bool {
-	var interestingLocationKeywords = []string{"germany", "deutschland", "poland", "berlin", "hamburg", "hamburg",
-		"hanover", "leipzig", "dresden"}
-	lowerLocation := strings.ToLower(location)
-	for _, loc := range interestingLocationKeywords {
-		if strings.Contains(lowerLocation, strings.ToLower(loc)) {
-			return true
-		}
+// MustInitCsv makes sure we have a csv to write to. Without a header the csv is appended to.
+func MustInitCsv(csvPath string, header []string) *csv.Writer {
+	w, err := initCsv(csvPath, header)
+	if err != nil {
+		log.WithError(err).Fatal()
 	}
 
-	return false
+	return w
 }
 
-// MustInitCsv makes sure we have a csv to write to
-func MustInitCsv(csvPath string, writeHeader bool) *csv.Writer {
-	var (
-		csvFile *os.File
-		err     error
-	)
-	csvFile, err = os.OpenFile(csvPath, os.O_WRONLY|os.O_CREATE, 0666)
+// initCsv opens the csv to write to, writing the header first. Without a header the csv is appended to.
+func initCsv(csvPath string, header []string) (*csv.Writer, error) {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	if header != nil {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	csvFile, err := os.OpenFile(csvPath, flags, 0666)
 	if err != nil {
-		log.WithError(err).Fatal()
+		return nil, err
 	}
 	w := csv.NewWriter(csvFile)
 
-	if writeHeader {
-		w.Write([]string{
-			"Login",
-			"Location",
-			"Email",
-			"Name",
-			"Company",
-			"Bio",
-			"Registered",
-			"Followers",
-			"Following",
-			"Organisations",
-			"Hireable",
-		})
+	if header != nil {
+		w.Write(header)
 		w.Flush()
 	}
 	if err := w.Error(); err != nil {
-		log.WithError(err).Fatal()
+		return nil, err
 	}
 
-	return w
+	return w, nil
 }
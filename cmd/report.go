@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/report"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var reportFlags struct {
+	as, out string
+}
+
+// reportCmd renders a run's report
+var reportCmd = &cobra.Command{
+	Use:   "report [run]",
+	Short: "renders a self-contained html or markdown report of a crawl",
+	Long: `Renders the report of a recorded run (the last one by default): the repos analyzed, the crawl
+stats and api cost, the candidates ranked by score with their interactions and the location and
+language distributions, drawn as inline svg charts in the html report.`,
+	Example: "gh-recruiter report --out report.html\ngh-recruiter report previous --as md --out report.md",
+	PreRun:  preRunDnc,
+	Run:     runReport,
+	Args:    cobra.MaximumNArgs(1),
+}
+
+func init() {
+	reportCmd.Flags().StringVar(&reportFlags.as, "as", report.FormatHTML, "report format, html or md")
+	reportCmd.Flags().StringVarP(&reportFlags.out, "out", "o", "", "file the report is written to, stdout if empty")
+
+	rootCmd.AddCommand(reportCmd)
+}
+
+func runReport(cmd *cobra.Command, args []string) {
+	if reportFlags.as != report.FormatHTML && reportFlags.as != report.FormatMarkdown {
+		log.Fatalf("unknown report format %s, expecting %s or %s", reportFlags.as, report.FormatHTML,
+			report.FormatMarkdown)
+	}
+
+	ids, err := States.RunIDs()
+	if err != nil {
+		log.WithError(err).Fatal("couldn't list the runs")
+	}
+	id := runLast
+	if len(args) > 0 {
+		id = args[0]
+	}
+	run, err := States.LoadRun(resolveRunID(ids, id))
+	if err != nil {
+		log.WithError(err).Fatal()
+	}
+
+	// people on the do-not-contact list stay out of the report
+	var candidates []*candidate.Candidate
+	for _, c := range run.Candidates {
+		if contactable(c.Login(), c.User.ID) {
+			candidates = append(candidates, c)
+		}
+	}
+
+	var w io.Writer = os.Stdout
+	if reportFlags.out != "" {
+		f, err := os.OpenFile(reportFlags.out, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+		if err != nil {
+			log.WithError(err).Fatal()
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err = report.New(run, candidates, time.Now()).Write(w, reportFlags.as); err != nil {
+		log.WithError(err).Fatal("couldn't render the report")
+	}
+	if reportFlags.out != "" {
+		log.WithFields(log.Fields{"run": run.ID, "file": reportFlags.out}).Info("report written")
+	}
+}
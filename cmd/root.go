@@ -3,6 +3,9 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
+
+	"github.com/florinutz/gh-recruiter/output"
 
 	log "github.com/sirupsen/logrus"
 
@@ -16,6 +19,7 @@ var veep *viper.Viper
 var rootConfig struct {
 	cfgFile string
 	verbose bool
+	format  string
 }
 
 // rootCmd represents the base command when called without any subcommands
@@ -36,6 +40,7 @@ func Execute() {
 const (
 	configName      = ".gh-recruiter"
 	rootFlagVerbose = "verbose"
+	rootFlagFormat  = "format"
 )
 
 func init() {
@@ -53,11 +58,24 @@ func init() {
 	rootCmd.PersistentFlags().BoolVarP(&rootConfig.verbose, rootFlagVerbose, "v", false,
 		"Verbose?")
 
+	rootCmd.PersistentFlags().StringVar(&rootConfig.format, rootFlagFormat, output.FormatCsv,
+		fmt.Sprintf("stdout format, one of %s", strings.Join(output.Formats, ", ")))
+
 	veep.BindPFlag("verbose", rootCmd.Flag(rootFlagVerbose))
 
 	cobra.OnInitialize(initConfig)
 }
 
+// mustStdout returns a writer printing records to stdout in the chosen format
+func mustStdout(header []string) output.Writer {
+	w, err := output.New(rootConfig.format, os.Stdout, header)
+	if err != nil {
+		log.WithError(err).Fatal()
+	}
+
+	return w
+}
+
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
 	if rootConfig.cfgFile != "" {
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/state"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// currentRun records the running crawl, nil if there's no state store
+var currentRun *state.Run
+
+// runCandidates are the candidates reported by the running crawl
+var runCandidates = candidate.NewSet()
+
+// preRunCrawl prepares the crawl commands, which get recorded as runs
+func preRunCrawl(cmd *cobra.Command, args []string) {
+	preRunRepo(cmd, args)
+	startRun(cmd.Name(), args)
+}
+
+// startRun starts recording a crawl
+func startRun(command string, args []string) {
+	runCandidates = candidate.NewSet()
+	if Fetcher.Stats != nil {
+		Fetcher.Stats.Reset()
+	}
+	if States != nil {
+		currentRun = state.NewRun(command, args, withoutTokens(veep.AllSettings()), time.Now())
+	}
+}
+
+// recordRepo adds the repo to the running crawl's repos
+func recordRepo(nameWithOwner string) {
+	if currentRun != nil {
+		currentRun.AddRepo(nameWithOwner)
+	}
+}
+
+// recordCandidate adds the candidate to the running crawl's candidates
+func recordCandidate(c *candidate.Candidate) {
+	runCandidates.Put(c)
+}
+
+// saveRun records the finished crawl
+func saveRun() {
+	if currentRun == nil {
+		return
+	}
+
+	currentRun.Finished = time.Now()
+	currentRun.Candidates = runCandidates.All()
+	if Fetcher.Stats != nil {
+		currentRun.Stats = Fetcher.Stats.Snapshot()
+	}
+	if err := States.SaveRun(currentRun); err != nil {
+		log.WithError(err).Error("couldn't record the run")
+		return
+	}
+	log.WithField("run", currentRun.ID).Info("run recorded")
+}
+
+// postRunCrawl saves what the crawl commands found
+func postRunCrawl(cmd *cobra.Command, args []string) {
+	saveCandidateDB()
+	saveRun()
+	writeXlsx(RepoCmdConfig.Xlsx, runCandidates)
+}
+
+// withoutTokens strips the github tokens from the settings, at any depth
+func withoutTokens(settings map[string]interface{}) map[string]interface{} {
+	stripped := map[string]interface{}{}
+	for key, value := range settings {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			value = withoutTokens(v)
+		case []interface{}:
+			values := make([]interface{}, len(v))
+			for i, item := range v {
+				if m, ok := item.(map[string]interface{}); ok {
+					item = withoutTokens(m)
+				}
+				values[i] = item
+			}
+			value = values
+		}
+		if key != "tokens" {
+			stripped[key] = value
+		}
+	}
+
+	return stripped
+}
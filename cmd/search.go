@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/fetch"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	searchFlagLocation    = "location"
+	searchFlagLanguage    = "language"
+	searchFlagFollowers   = "followers"
+	searchFlagRepos       = "repos"
+	searchFlagCreatedFrom = "created-from"
+	searchFlagCreatedTo   = "created-to"
+	searchFlagCsvOutput   = "output"
+)
+
+// searchFlags holds the search criteria, the dates being parsed in the pre run
+var searchFlags struct {
+	search                 fetch.UserSearch
+	createdFrom, createdTo string
+	csv                    string
+}
+
+// searchCmd finds users through github's user search
+var searchCmd = &cobra.Command{
+	Use:     "search",
+	Short:   "finds users by location, language, followers and such",
+	Example: `gh-recruiter search --location Hamburg --language go --followers ">50"`,
+	PreRun:  preRunSearch,
+	PostRun: postRunCrawl,
+	Run:     runSearch,
+	Args:    cobra.NoArgs,
+}
+
+func init() {
+	searchCmd.Flags().StringVar(&searchFlags.search.Location, searchFlagLocation, "",
+		"location the users entered in their profile")
+	searchCmd.Flags().StringVarP(&searchFlags.search.Language, searchFlagLanguage, "l", "",
+		"language of the users' repos")
+	searchCmd.Flags().StringVar(&searchFlags.search.Followers, searchFlagFollowers, "",
+		`followers range, e.g. ">50" or "10..100"`)
+	searchCmd.Flags().StringVar(&searchFlags.search.Repos, searchFlagRepos, "",
+		`public repos range, e.g. ">5"`)
+	searchCmd.Flags().StringVar(&searchFlags.createdFrom, searchFlagCreatedFrom, "",
+		"only accounts created on or after this date (YYYY-MM-DD)")
+	searchCmd.Flags().StringVar(&searchFlags.createdTo, searchFlagCreatedTo, "",
+		"only accounts created on or before this date (YYYY-MM-DD)")
+	searchCmd.Flags().StringVarP(&searchFlags.csv, searchFlagCsvOutput, "o", "",
+		"Csv output file")
+	addXlsxFlag(searchCmd)
+
+	rootCmd.AddCommand(searchCmd)
+}
+
+func preRunSearch(cmd *cobra.Command, args []string) {
+	var err error
+	if searchFlags.createdFrom != "" {
+		if searchFlags.search.CreatedFrom, err = time.Parse("2006-01-02", searchFlags.createdFrom); err != nil {
+			log.WithError(err).Fatal("invalid --" + searchFlagCreatedFrom)
+		}
+	}
+	if searchFlags.createdTo != "" {
+		if searchFlags.search.CreatedTo, err = time.Parse("2006-01-02", searchFlags.createdTo); err != nil {
+			log.WithError(err).Fatal("invalid --" + searchFlagCreatedTo)
+		}
+	}
+
+	// github already matched the users' location
+	if searchFlags.search.Location != "" {
+		locationKeywords = nil
+	}
+
+	preRunCrawl(cmd, args)
+}
+
+func runSearch(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+	log.WithField("query", searchFlags.search.Query()).Info("searching")
+
+	users, err := Fetcher.SearchAllUsers(ctx, searchFlags.search)
+	if err != nil {
+		if len(users) == 0 {
+			log.WithError(err).Fatal("search failed")
+		}
+		log.WithError(err).Warnf("search failed, showing the %d users found so far", len(users))
+	}
+
+	writer := searchCsv()
+	for _, user := range users {
+		handleFetchedUser(ctx, fetch.UserFetchResult{Login: string(user.Login), User: user}, writer)
+	}
+	stdout.Flush()
+}
+
+func searchCsv() *csv.Writer {
+	if searchFlags.csv == "" {
+		return nil
+	}
+
+	return MustInitCsv(searchFlags.csv, candidate.CsvHeader)
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/api"
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/state"
+	"github.com/florinutz/gh-recruiter/web"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	serveFlagListen = "listen"
+	serveFlagAPIKey = "api-key"
+)
+
+var serveFlags struct {
+	listen, apiKey string
+}
+
+// ServeConfig configures the http api
+type ServeConfig struct {
+	Listen string `toml:"listen" comment:"address the api listens on"`
+	APIKey string `toml:"api_key" mapstructure:"api_key" comment:"key the api clients send as a bearer token or in the X-API-Key header"`
+}
+
+// serveCmd serves the crawls and the candidates over http
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "serves crawls and candidates over a json http api and a web ui",
+	Long: `Serves a json api authenticated by an api key:
+
+  POST /api/crawls                     {"repo": "owner/name", "prs": true} starts a crawl
+  GET  /api/crawls[/<id>]              shows the crawls' progress
+  GET  /api/candidates                 lists the candidates, filtered by status, tag, repo, role,
+                                       min_score and q, sorted by score, last_seen or login
+  GET  /api/candidates/<login>         shows a candidate
+  POST /api/candidates/<login>/status  {"status": "contacted"} moves a candidate through the pipeline
+
+  POST /api/candidates/<login>/dnc     {"reason": "asked by email"} adds a candidate to the do-not-contact list
+
+Crawls run one at a time and their candidates are tracked in the candidate pipeline.
+The root path serves a web ui for reviewing the candidates, asking for the api key.`,
+	Example: "gh-recruiter serve --listen 127.0.0.1:8090 --api-key s3cr3t",
+	PreRun:  preRunServe,
+	Run:     runServe,
+	Args:    cobra.NoArgs,
+}
+
+func init() {
+	addCrawlFlags(serveCmd)
+	serveCmd.Flags().StringVar(&serveFlags.listen, serveFlagListen, "127.0.0.1:8090",
+		"address the api listens on, overriding the config")
+	serveCmd.Flags().StringVar(&serveFlags.apiKey, serveFlagAPIKey, "", "api key, overriding the config")
+
+	rootCmd.AddCommand(serveCmd)
+}
+
+func preRunServe(cmd *cobra.Command, args []string) {
+	preRunRepo(cmd, args)
+
+	if cmd.Flags().Changed(serveFlagListen) || RepoCmdConfig.Serve.Listen == "" {
+		RepoCmdConfig.Serve.Listen = serveFlags.listen
+	}
+	if serveFlags.apiKey != "" {
+		RepoCmdConfig.Serve.APIKey = serveFlags.apiKey
+	}
+	if RepoCmdConfig.Serve.APIKey == "" {
+		log.Fatalf("no api key, set it in the config or with --%s", serveFlagAPIKey)
+	}
+	if candidateDB == nil {
+		log.Fatal("there's no candidate pipeline to serve")
+	}
+}
+
+// newAPIServer returns an api server owning the candidate pipeline, which the crawls then leave alone
+func newAPIServer() *api.Server {
+	db := candidateDB
+	candidateDB = nil
+
+	server := api.NewServer(RepoCmdConfig.Serve.APIKey, db, States.SaveCandidates, serveCrawl)
+	server.Contactable = func(c *candidate.Candidate) bool {
+		return contactable(c.Login(), c.User.ID)
+	}
+	server.DoNotContact = func(c *candidate.Candidate, reason string) error {
+		if doNotContact == nil {
+			return errors.New("there's no do-not-contact list")
+		}
+		id, _ := c.User.ID.(string)
+		doNotContact.Add(state.DoNotContactEntry{Login: c.Login(), ID: id, Reason: reason, Added: time.Now()})
+
+		return States.SaveDoNotContact(doNotContact)
+	}
+
+	return server
+}
+
+func runServe(cmd *cobra.Command, args []string) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// the failed crawls continue from their checkpoints the next time they run
+	repoFlags.resume = true
+	server := newAPIServer()
+	go server.Run(ctx)
+
+	mux := http.NewServeMux()
+	mux.Handle("/api/", server.Handler())
+	mux.Handle("/", web.Handler())
+
+	httpServer := &http.Server{Addr: RepoCmdConfig.Serve.Listen, Handler: mux}
+	go func() {
+		<-ctx.Done()
+		httpServer.Shutdown(context.Background())
+	}()
+
+	log.WithField("address", httpServer.Addr).Info("serving")
+	if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.WithError(err).Fatal("server failed")
+	}
+}
+
+// serveCrawl runs a crawl requested through the api as a recorded run. Like the other crawls, it sets
+// the current run, its candidates, the fetcher's stats and candidateFound, which is only safe because
+// api.Server.Run runs a single crawl at a time.
+func serveCrawl(ctx context.Context, req api.CrawlRequest, found func(c *candidate.Candidate)) (
+	*candidate.Set, error) {
+	ownerName := strings.Split(req.Repo, "/")
+	if len(ownerName) != 2 {
+		return nil, errors.Errorf("invalid repo %q", req.Repo)
+	}
+	r := configuredRepo(ownerName[0], ownerName[1])
+	if req.Forkers || req.PRs || req.Stargazers {
+		r.Forkers, r.PRs, r.Stargazers = req.Forkers, req.PRs, req.Stargazers
+	}
+
+	startRun("serve", []string{req.Repo})
+	candidateFound = found
+	defer func() {
+		candidateFound = nil
+		saveRun()
+	}()
+
+	// the candidates found before a failure are kept
+	return r.candidates, r.analyze(ctx)
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/florinutz/gh-recruiter/fetch"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// userCmd looks up individual users
+var userCmd = &cobra.Command{
+	Use:    "user <login>...",
+	Short:  "shows the enriched profiles of the given users",
+	PreRun: preRunRepo,
+	Run:    runUser,
+	Args:   cobra.MinimumNArgs(1),
+}
+
+func init() {
+	rootCmd.AddCommand(userCmd)
+}
+
+func runUser(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+	out := mustStdout(fetch.ProfileCsvHeader)
+
+	for _, login := range args {
+		profile, err := Fetcher.GetProfile(ctx, login)
+		if err != nil {
+			log.WithError(err).WithField("login", login).Error("couldn't fetch profile")
+			continue
+		}
+		if !contactable(login, profile.ID) {
+			log.WithField("login", login).Warn("on the do-not-contact list, not shown")
+			continue
+		}
+		if err = out.Write(profile); err != nil {
+			log.WithError(err).Fatal("couldn't print profile")
+		}
+	}
+
+	if err := out.Flush(); err != nil {
+		log.WithError(err).Fatal("couldn't print profiles")
+	}
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/notify"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	watchFlagInterval = "interval"
+	watchFlagOnce     = "once"
+	watchFlagFields   = "fields"
+	watchFlagStatuses = "statuses"
+	watchFlagFile     = "notify-file"
+	watchFlagWebhook  = "webhook"
+)
+
+var watchFlags struct {
+	interval time.Duration
+	once     bool
+	fields   []string
+	statuses []string
+	file     string
+	webhook  string
+}
+
+// NotifyConfig configures where the notifications go, besides stdout
+type NotifyConfig struct {
+	File           string            `toml:"file" commented:"true" comment:"file the notifications are appended to, as json lines" omitempty:"true"`
+	Webhook        string            `toml:"webhook" commented:"true" comment:"url the notifications are POSTed to, as json" omitempty:"true"`
+	WebhookHeaders map[string]string `toml:"webhook_headers" mapstructure:"webhook_headers" commented:"true" comment:"headers sent along with the webhook requests" omitempty:"true"`
+}
+
+// watchCmd watches the tracked candidates' profiles
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "notifies about changes in the tracked candidates' profiles",
+	Long: fmt.Sprintf(`Periodically re-fetches the profiles of the candidates tracked in the pipeline and notifies
+about the changes of the watched fields, through stdout, a file and a webhook.
+The fields that can be watched are %s.`, strings.Join(candidate.ProfileFieldNames(), ", ")),
+	Example: "gh-recruiter watch --interval 12h --webhook https://hooks.example.com/recruiting",
+	PreRun:  preRunWatch,
+	Run:     runWatch,
+	Args:    cobra.NoArgs,
+}
+
+func init() {
+	watchCmd.Flags().DurationVar(&watchFlags.interval, watchFlagInterval, 24*time.Hour, "time between checks")
+	watchCmd.Flags().BoolVar(&watchFlags.once, watchFlagOnce, false, "check once and exit")
+	watchCmd.Flags().StringSliceVar(&watchFlags.fields, watchFlagFields, []string{"hireable", "company"},
+		"watched profile fields")
+	watchCmd.Flags().StringSliceVar(&watchFlags.statuses, watchFlagStatuses, nil,
+		"only watch the candidates having these statuses")
+	watchCmd.Flags().StringVar(&watchFlags.file, watchFlagFile, "", "file the notifications are appended to")
+	watchCmd.Flags().StringVar(&watchFlags.webhook, watchFlagWebhook, "", "url the notifications are POSTed to")
+
+	rootCmd.AddCommand(watchCmd)
+}
+
+func preRunWatch(cmd *cobra.Command, args []string) {
+	for _, field := range watchFlags.fields {
+		if _, ok := candidate.ProfileFields[field]; !ok {
+			log.Fatalf("unknown field %s, expecting one of %s", field,
+				strings.Join(candidate.ProfileFieldNames(), ", "))
+		}
+	}
+	for _, status := range watchFlags.statuses {
+		if _, err := candidate.ParseStatus(status); err != nil {
+			log.WithError(err).Fatal()
+		}
+	}
+
+	preRunRepo(cmd, args)
+	if candidateDB == nil {
+		log.Fatal("there's no candidate pipeline to watch")
+	}
+
+	if watchFlags.file != "" {
+		RepoCmdConfig.Notify.File = watchFlags.file
+	}
+	if watchFlags.webhook != "" {
+		RepoCmdConfig.Notify.Webhook = watchFlags.webhook
+	}
+}
+
+// buildSinks returns stdout followed by the configured notification sinks
+func buildSinks(conf NotifyConfig) notify.Sinks {
+	sinks := notify.Sinks{notify.Writer{W: os.Stdout}}
+	if conf.File != "" {
+		sinks = append(sinks, &notify.File{Path: conf.File})
+	}
+	if conf.Webhook != "" {
+		sinks = append(sinks, notify.Webhook{URL: conf.Webhook, Headers: conf.WebhookHeaders})
+	}
+
+	return sinks
+}
+
+func runWatch(cmd *cobra.Command, args []string) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	sinks := buildSinks(RepoCmdConfig.Notify)
+	for {
+		watchCandidates(ctx, sinks)
+		if watchFlags.once {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(watchFlags.interval):
+		}
+	}
+}
+
+// watchCandidates re-fetches the watched candidates once, notifying about their changes
+func watchCandidates(ctx context.Context, sink notify.Sink) {
+	fresh := Fetcher
+	fresh.Fresh = true
+
+	checked, changed := 0, 0
+	for _, r := range candidateDB.Sorted() {
+		if ctx.Err() != nil {
+			break
+		}
+		if !watched(r) {
+			continue
+		}
+
+		user, err := fresh.GetUser(ctx, r.Candidate.Login())
+		if err != nil {
+			log.WithError(err).WithField("login", r.Candidate.Login()).Warn("couldn't fetch the profile")
+			continue
+		}
+		checked++
+
+		now := time.Now()
+		for _, change := range r.Refresh(user, now, watchFlags.fields...) {
+			changed++
+			if err = sink.Notify(ctx, notify.Notification{Change: change, At: now}); err != nil {
+				log.WithError(err).WithField("login", change.Login).Warn("notification failed")
+			}
+		}
+	}
+	saveCandidateDB()
+
+	log.WithFields(log.Fields{"checked": checked, "changes": changed}).Info("candidates watched")
+}
+
+// watched tells whether the tracked candidate is to be watched
+func watched(r *candidate.Record) bool {
+	if !contactable(r.Candidate.Login(), r.Candidate.User.ID) {
+		return false
+	}
+	if len(watchFlags.statuses) == 0 {
+		return true
+	}
+	for _, status := range watchFlags.statuses {
+		if strings.EqualFold(status, string(r.Status)) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"os"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/xlsx"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// addXlsxFlag adds the flag writing the crawl's candidates to an excel workbook
+func addXlsxFlag(cmd *cobra.Command) {
+	cmd.Flags().String(repoFlagXlsx, "",
+		"excel workbook the candidates are written to, with a summary and a sheet per role")
+}
+
+// writeXlsx writes the candidates to the workbook at path, if there is one
+func writeXlsx(path string, candidates *candidate.Set) {
+	if path == "" {
+		return
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		log.WithError(err).Error("couldn't create the workbook")
+		return
+	}
+	defer f.Close()
+
+	if err = xlsx.Candidates(candidates.All(), time.Now()).Write(f); err != nil {
+		log.WithError(err).Error("couldn't write the workbook")
+		return
+	}
+	log.WithFields(log.Fields{"file": path, "candidates": candidates.Len()}).Info("workbook written")
+}
@@ -0,0 +1,308 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/pkg/errors"
+)
+
+// The formats a profile can export to
+const (
+	FormatCsv   = "csv"
+	FormatVCard = "vcard"
+)
+
+// Column maps the candidates to one of the target's columns, or to a vCard property
+type Column struct {
+	Header string `toml:"header"`
+	// Value is a text/template executed on the candidate's Fields
+	Value string `toml:"value"`
+}
+
+// Profile maps the candidates to the schema an applicant tracking system imports
+type Profile struct {
+	Name    string   `toml:"name"`
+	Format  string   `toml:"format" comment:"csv or vcard"`
+	Columns []Column `toml:"columns"`
+}
+
+// Builtin are the profiles available without configuration
+var Builtin = map[string]Profile{
+	"greenhouse": {Name: "greenhouse", Format: FormatCsv, Columns: []Column{
+		{"First Name", "{{.FirstName}}"},
+		{"Last Name", "{{.LastName}}"},
+		{"Company", "{{.Company}}"},
+		{"Email", "{{.Email}}"},
+		{"Website", "{{.Profile}}"},
+		{"Social Media", "{{.Profile}}"},
+		{"Location", "{{.Location}}"},
+		{"Source", "GitHub"},
+		{"Tags", "{{join .Tags \",\"}}"},
+		{"Notes", "{{.Roles}}, score {{.Score}}"},
+	}},
+	"lever": {Name: "lever", Format: FormatCsv, Columns: []Column{
+		{"Name", "{{.Name}}"},
+		{"Email", "{{.Email}}"},
+		{"Current Company", "{{.Company}}"},
+		{"Location", "{{.Location}}"},
+		{"Links", "{{.Profile}}"},
+		{"Sources", "GitHub"},
+		{"Tags", "{{join .Tags \",\"}}"},
+		{"Notes", "{{.Roles}}; top languages: {{join .TopLanguages \" \"}}"},
+	}},
+	"vcard": {Name: "vcard", Format: FormatVCard, Columns: []Column{
+		{"FN", "{{.Name}}"},
+		{"N", "{{.LastName}};{{.FirstName}};;;"},
+		{"NICKNAME", "{{.Login}}"},
+		{"EMAIL;TYPE=INTERNET", "{{.Email}}"},
+		{"ORG", "{{.Company}}"},
+		{"ADR;TYPE=HOME", ";;;{{.Location}};;;"},
+		{"URL", "{{.Profile}}"},
+		{"NOTE", "{{.Roles}}"},
+		{"CATEGORIES", "{{join .Tags \",\"}}"},
+	}},
+}
+
+// Names returns the names of the builtin and the given profiles, sorted
+func Names(configured []Profile) (names []string) {
+	seen := map[string]bool{}
+	for name := range Builtin {
+		seen[name] = true
+	}
+	for _, p := range configured {
+		seen[p.Name] = true
+	}
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return
+}
+
+// Find returns the named profile, the configured ones overriding the builtin ones
+func Find(name string, configured []Profile) (Profile, bool) {
+	for _, p := range configured {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	p, ok := Builtin[name]
+
+	return p, ok
+}
+
+// Fields are what the profiles' columns can refer to
+type Fields struct {
+	Login        string
+	Name         string
+	FirstName    string
+	LastName     string
+	Email        string
+	Location     string
+	Company      string
+	Bio          string
+	Profile      string
+	Hireable     bool
+	Followers    int
+	Score        string
+	Status       string
+	Tags         []string
+	Repos        []string
+	Roles        string
+	TopLanguages []string
+	Timezone     string
+	LastActive   string
+	FirstSeen    string
+}
+
+// NewFields computes the fields of a tracked candidate
+func NewFields(r *candidate.Record, now time.Time) Fields {
+	c, u := &r.Candidate, r.Candidate.User
+	f := Fields{
+		Login:        c.Login(),
+		Name:         strings.TrimSpace(string(u.Name)),
+		Email:        string(u.Email),
+		Location:     string(u.Location),
+		Company:      string(u.Company),
+		Bio:          strings.Join(strings.Fields(string(u.Bio)), " "),
+		Profile:      "https://github.com/" + c.Login(),
+		Hireable:     bool(u.IsHireable),
+		Followers:    int(u.Followers.TotalCount),
+		Score:        strconv.FormatFloat(c.Score(now), 'f', 2, 64),
+		Status:       string(r.Status),
+		Tags:         r.Tags,
+		Repos:        c.Repos(),
+		Roles:        c.Summary(),
+		TopLanguages: c.Languages.Top(candidate.TopLanguagesCount),
+		FirstSeen:    r.FirstSeen.Format("2006-01-02"),
+	}
+	if f.Name == "" {
+		f.Name = f.Login
+	}
+	if names := strings.Fields(f.Name); len(names) > 1 {
+		f.FirstName, f.LastName = strings.Join(names[:len(names)-1], " "), names[len(names)-1]
+	} else {
+		f.FirstName, f.LastName = f.Name, f.Login
+	}
+	if c.Timezone != nil {
+		f.Timezone = c.Timezone.String()
+	}
+	if last := c.LastActive(); !last.IsZero() {
+		f.LastActive = last.Format("2006-01-02")
+	}
+
+	return f
+}
+
+// escaped returns a copy of the fields having their texts escaped
+func (f Fields) escaped(escape func(string) string) Fields {
+	v := reflect.ValueOf(&f).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		switch field := v.Field(i); field.Kind() {
+		case reflect.String:
+			field.SetString(escape(field.String()))
+		case reflect.Slice:
+			texts := make([]string, field.Len())
+			for j := range texts {
+				texts[j] = escape(field.Index(j).String())
+			}
+			field.Set(reflect.ValueOf(texts))
+		}
+	}
+
+	return f
+}
+
+// Exporter writes candidates following a profile
+type Exporter struct {
+	profile Profile
+	values  []*template.Template
+}
+
+// New compiles the profile's columns
+func New(p Profile) (*Exporter, error) {
+	if p.Format != FormatCsv && p.Format != FormatVCard {
+		return nil, errors.Errorf("profile %s has the unknown format %q", p.Name, p.Format)
+	}
+	if len(p.Columns) == 0 {
+		return nil, errors.Errorf("profile %s has no columns", p.Name)
+	}
+
+	e := &Exporter{profile: p}
+	funcs := template.FuncMap{"join": strings.Join}
+	for _, col := range p.Columns {
+		tmpl, err := template.New(col.Header).Funcs(funcs).Option("missingkey=error").Parse(col.Value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "profile %s, column %s", p.Name, col.Header)
+		}
+		e.values = append(e.values, tmpl)
+	}
+
+	return e, nil
+}
+
+// row computes the candidate's values for the profile's columns
+func (e *Exporter) row(f Fields) ([]string, error) {
+	row := make([]string, len(e.values))
+	for i, tmpl := range e.values {
+		var b bytes.Buffer
+		if err := tmpl.Execute(&b, f); err != nil {
+			return nil, errors.Wrapf(err, "profile %s, column %s", e.profile.Name, e.profile.Columns[i].Header)
+		}
+		row[i] = b.String()
+	}
+
+	return row, nil
+}
+
+// Write exports the candidates
+func (e *Exporter) Write(w io.Writer, records []*candidate.Record, now time.Time) error {
+	if e.profile.Format == FormatVCard {
+		return e.writeVCards(w, records, now)
+	}
+
+	out := csv.NewWriter(w)
+	header := make([]string, len(e.profile.Columns))
+	for i, col := range e.profile.Columns {
+		header[i] = col.Header
+	}
+	out.Write(header)
+	for _, r := range records {
+		row, err := e.row(NewFields(r, now))
+		if err != nil {
+			return err
+		}
+		out.Write(row)
+	}
+	out.Flush()
+
+	return out.Error()
+}
+
+var vCardEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, ",", `\,`, ";", `\;`)
+
+// vCardLineLength is the maximum length of the vCard lines, in octets, the longer ones being folded
+const vCardLineLength = 75
+
+func (e *Exporter) writeVCards(w io.Writer, records []*candidate.Record, now time.Time) error {
+	for _, r := range records {
+		f := NewFields(r, now)
+		row, err := e.row(f)
+		if err != nil {
+			return err
+		}
+		// structured values keep their separators, only the fields in between being escaped
+		structured, err := e.row(f.escaped(vCardEscaper.Replace))
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprint(w, "BEGIN:VCARD\r\nVERSION:3.0\r\n")
+		for i, col := range e.profile.Columns {
+			if strings.Trim(row[i], "; ") == "" {
+				continue
+			}
+			value := vCardEscaper.Replace(row[i])
+			if strings.Contains(col.Value, ";") || strings.HasPrefix(col.Header, "CATEGORIES") {
+				value = structured[i]
+			}
+			fmt.Fprint(w, foldVCardLine(col.Header+":"+value))
+		}
+		if _, err = fmt.Fprint(w, "END:VCARD\r\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// foldVCardLine terminates the line, folding it into continuation lines starting with a space
+// when it's too long. Multi-byte characters aren't split.
+func foldVCardLine(line string) string {
+	var b strings.Builder
+	length := 0
+	for _, r := range line {
+		size := utf8.RuneLen(r)
+		if length+size > vCardLineLength {
+			b.WriteString("\r\n ")
+			length = 1
+		}
+		b.WriteRune(r)
+		length += size
+	}
+	b.WriteString("\r\n")
+
+	return b.String()
+}
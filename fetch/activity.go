@@ -0,0 +1,99 @@
+package fetch
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// Contributions counts a user's contributions
+type Contributions struct {
+	TotalCommitContributions            githubv4.Int
+	TotalPullRequestContributions       githubv4.Int
+	TotalPullRequestReviewContributions githubv4.Int
+	TotalIssueContributions             githubv4.Int
+}
+
+// Total returns the sum of all the contributions
+func (c Contributions) Total() int {
+	return int(c.TotalCommitContributions + c.TotalPullRequestContributions +
+		c.TotalPullRequestReviewContributions + c.TotalIssueContributions)
+}
+
+// Activity is a user's contribution volume and recency. It's too costly for the bulk user queries,
+// so it's fetched per login, only for the users who need it.
+type Activity struct {
+	// Contributions covers the last year
+	Contributions Contributions `graphql:"contributionsCollection"`
+	RecentRepos   struct {
+		Nodes []struct {
+			PushedAt githubv4.DateTime
+		}
+	} `graphql:"recentRepos: repositories(first: 1, ownerAffiliations: [OWNER, COLLABORATOR], orderBy: {field: PUSHED_AT, direction: DESC})"`
+	RecentPullRequests struct {
+		Nodes []struct {
+			CreatedAt githubv4.DateTime
+		}
+	} `graphql:"recentPullRequests: pullRequests(first: 1, orderBy: {field: CREATED_AT, direction: DESC})"`
+}
+
+// LastActive returns the last time the user pushed to one of their repos or opened a PR,
+// or the zero time if they never did
+func (a Activity) LastActive() (last time.Time) {
+	for _, repo := range a.RecentRepos.Nodes {
+		if repo.PushedAt.After(last) {
+			last = repo.PushedAt.Time
+		}
+	}
+	for _, pr := range a.RecentPullRequests.Nodes {
+		if pr.CreatedAt.After(last) {
+			last = pr.CreatedAt.Time
+		}
+	}
+
+	return
+}
+
+// ActivityCsvHeader is the header matching Activity.FormatForCsv
+var ActivityCsvHeader = []string{
+	"Commits (12m)",
+	"PRs (12m)",
+	"Reviews (12m)",
+	"Issues (12m)",
+	"Last active",
+}
+
+// FormatForCsv returns a []string representation of the activity
+func (a Activity) FormatForCsv() (result []string) {
+	result = []string{
+		strconv.Itoa(int(a.Contributions.TotalCommitContributions)),
+		strconv.Itoa(int(a.Contributions.TotalPullRequestContributions)),
+		strconv.Itoa(int(a.Contributions.TotalPullRequestReviewContributions)),
+		strconv.Itoa(int(a.Contributions.TotalIssueContributions)),
+		"",
+	}
+	if last := a.LastActive(); !last.IsZero() {
+		result[len(result)-1] = last.Format("02-Jan-2006")
+	}
+
+	return
+}
+
+// GetActivity retrieves the user's contributions of the last year and the time they were last active
+func (g *GithubFetcher) GetActivity(ctx context.Context, login string) (Activity, error) {
+	var q struct {
+		User      Activity `graphql:"user(login:$login)"`
+		RateLimit rateLimit
+	}
+	vars := map[string]interface{}{
+		"login": githubv4.String(login),
+	}
+
+	if err := g.Query(ctx, &q, vars); err != nil {
+		return Activity{}, err
+	}
+
+	return q.User, nil
+}
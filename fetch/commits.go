@@ -0,0 +1,51 @@
+package fetch
+
+import (
+	"context"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// GetCommitTimes returns the authored dates of the user's latest commits in their recently pushed repos
+func (g *GithubFetcher) GetCommitTimes(ctx context.Context, login string, id githubv4.ID) ([]time.Time, error) {
+	var q struct {
+		User struct {
+			Repositories struct {
+				Nodes []struct {
+					DefaultBranchRef struct {
+						Target struct {
+							Commit struct {
+								History struct {
+									Nodes []struct {
+										AuthoredDate githubv4.DateTime
+									}
+								} `graphql:"history(first: $maxCommits, author: {id: $authorId})"`
+							} `graphql:"... on Commit"`
+						}
+					}
+				}
+			} `graphql:"repositories(first: $maxRepos, ownerAffiliations: [OWNER, COLLABORATOR], orderBy: {field: PUSHED_AT, direction: DESC})"`
+		} `graphql:"user(login:$login)"`
+		RateLimit rateLimit
+	}
+	vars := map[string]interface{}{
+		"login":      githubv4.String(login),
+		"authorId":   githubv4.NewID(id), // as a pointer, so that the variable gets typed as ID
+		"maxRepos":   githubv4.Int(5),
+		"maxCommits": githubv4.Int(30),
+	}
+
+	if err := g.Query(ctx, &q, vars); err != nil {
+		return nil, err
+	}
+
+	var times []time.Time
+	for _, repo := range q.User.Repositories.Nodes {
+		for _, commit := range repo.DefaultBranchRef.Target.Commit.History.Nodes {
+			times = append(times, commit.AuthoredDate.Time)
+		}
+	}
+
+	return times, nil
+}
@@ -6,18 +6,22 @@ import (
 	"errors"
 	"math/rand"
 	"reflect"
+	"strings"
 	"time"
 
-	"google.golang.org/appengine/log"
-
 	"github.com/florinutz/gh-recruiter/cache"
 	"github.com/shurcooL/githubv4"
+	log "github.com/sirupsen/logrus"
 )
 
 // GithubFetcher provides caching for a github graphql client's queries
 type GithubFetcher struct {
 	Client *githubv4.Client
 	Cache  *cache.Cache
+	// Fresh skips the cache reads, the fetched data still being cached
+	Fresh bool
+	// Stats counts the queries, when set
+	Stats *Stats
 }
 
 // GetUser retrieves a gh user
@@ -26,7 +30,7 @@ func (g *GithubFetcher) GetUser(ctx context.Context, login string) (User, error)
 		User      User `graphql:"user(login:$login)"`
 		RateLimit rateLimit
 	}
-	vars := map[string]interface{}{"login": githubv4.String(login), "maxOrgs": githubv4.Int(3)}
+	vars := map[string]interface{}{"login": githubv4.String(login), "maxOrgs": githubv4.Int(MaxOrgs)}
 
 	err := g.Query(ctx, &q, vars)
 	if err != nil {
@@ -36,15 +40,21 @@ func (g *GithubFetcher) GetUser(ctx context.Context, login string) (User, error)
 	return q.User, nil
 }
 
+// IsNotFound tells whether the error is github's answer for a login that doesn't exist (anymore)
+func IsNotFound(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "Could not resolve to a User")
+}
+
 // Query wraps the client's query in order to cache it
 func (g *GithubFetcher) Query(ctx context.Context, q interface{}, variables map[string]interface{}) error {
 	if t := reflect.TypeOf(q); t.Kind() != reflect.Ptr {
 		return errors.New("incoming query is not a pointer")
 	}
 
-	if g.Cache != nil {
+	if g.Cache != nil && !g.Fresh {
 		if itemFromCache, err := g.Cache.ReadQuery(q, variables); err == nil {
-			q = itemFromCache
+			reflect.ValueOf(q).Elem().Set(reflect.ValueOf(itemFromCache).Elem())
+			g.Stats.cacheHit()
 			return nil
 		}
 	}
@@ -53,9 +63,12 @@ func (g *GithubFetcher) Query(ctx context.Context, q interface{}, variables map[
 	if err != nil {
 		return err
 	}
+	g.Stats.query(q)
 
 	if g.Cache != nil {
-		g.Cache.WriteQuery(q, variables)
+		if err = g.Cache.WriteQuery(q, variables); err != nil {
+			log.WithError(err).Debug("query not cached")
+		}
 	}
 
 	return nil
@@ -86,7 +99,7 @@ func (g *GithubFetcher) GetUsersByLogins(ctx context.Context, logins []string, w
 		case fetchedUser := <-out:
 			fetchCallback(ctx, fetchedUser, writer)
 		case <-time.After(10 * time.Second):
-			log.Warningf(ctx, "timeout")
+			log.Warn("timeout")
 		}
 	}
 
@@ -95,94 +108,137 @@ func (g *GithubFetcher) GetUsersByLogins(ctx context.Context, logins []string, w
 	}
 }
 
-// GetPRs returns PRs together with their interesting data
-func (g *GithubFetcher) GetPRs(ctx context.Context, repoOwner string, repoName string, after *githubv4.String,
-	depth int) (results []PrWithData, err error) {
+// GetPRs returns PRs together with their interesting data, walking as many pages as the paginator allows.
+// If since is set, only PRs updated after it are returned.
+// On error it returns the PRs fetched so far, the paginator's After cursor pointing past them.
+func (g *GithubFetcher) GetPRs(ctx context.Context, repoOwner string, repoName string, pager *Paginator,
+	since time.Time) (results []PrWithData, err error) {
 	const PrsPerBatch = 100
 
-	variables := map[string]interface{}{
-		"repositoryOwner":   githubv4.String(repoOwner),
-		"repositoryName":    githubv4.String(repoName),
-		"prsPerBatch":       githubv4.Int(PrsPerBatch),
-		"prItemsPerBatch":   githubv4.Int(100),
-		"prCommitsPerBatch": githubv4.Int(5), // a safe value so that we don't request too much data
-		"maxOrgs":           githubv4.Int(5),
-		"after":             after,
-	}
+	err = pager.Each(ctx, func(ctx context.Context, after *githubv4.String) (PageInfo, error) {
+		var q struct {
+			Repository struct {
+				PullRequests struct {
+					PageInfo PageInfo
+					Nodes    []PrWithData
+				} `graphql:"pullRequests(after: $after, first: $prsPerBatch, orderBy: {field: UPDATED_AT, direction: DESC})"`
+			} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
+			RateLimit rateLimit
+		}
 
-	var q struct {
-		Repository struct {
-			PullRequests struct {
-				PageInfo pageInfo
-				Nodes    []PrWithData
-			} `graphql:"pullRequests(after: $after, first: $prsPerBatch, orderBy: {field: UPDATED_AT, direction: DESC})"`
-		} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
-		RateLimit rateLimit
-	}
+		err := g.Query(ctx, &q, map[string]interface{}{
+			"repositoryOwner":   githubv4.String(repoOwner),
+			"repositoryName":    githubv4.String(repoName),
+			"prsPerBatch":       githubv4.Int(PrsPerBatch),
+			"prItemsPerBatch":   githubv4.Int(100),
+			"prCommitsPerBatch": githubv4.Int(5), // a safe value so that we don't request too much data
+			"maxOrgs":           githubv4.Int(MaxOrgs),
+			"after":             after,
+		})
+		if err != nil {
+			return PageInfo{}, err
+		}
 
-	err = g.Query(ctx, &q, variables)
-	if err != nil {
-		return
-	}
+		info := q.Repository.PullRequests.PageInfo
+		for _, pr := range q.Repository.PullRequests.Nodes {
+			if !since.IsZero() && !pr.UpdatedAt.After(since) {
+				info.HasNextPage = false // PRs are sorted by update time, so the rest are older
+				break
+			}
+			results = append(results, pr)
+		}
 
-	hasNextPage := bool(q.Repository.PullRequests.PageInfo.HasNextPage)
-	endCursor := &q.Repository.PullRequests.PageInfo.EndCursor
+		return info, nil
+	})
 
-	results = append(results, q.Repository.PullRequests.Nodes...)
+	return
+}
 
-	depth++
-	isNotDeepEnough := depth*PrsPerBatch <= 200
+// GetForkers gets forkers for the repo, the forks having the most stargazers first. If since is set, only forks
+// created after it are returned, newest first.
+// On error it returns the forks fetched so far, the paginator's After cursor pointing past them.
+func (g *GithubFetcher) GetForkers(ctx context.Context, repoOwner string, repoName string, pager *Paginator,
+	pageSize int, since time.Time) (results []Fork, err error) {
+	order := githubv4.RepositoryOrder{Field: githubv4.RepositoryOrderFieldStargazers, Direction: githubv4.OrderDirectionDesc}
+	if !since.IsZero() {
+		// the walk stops at the first fork older than since
+		order.Field = githubv4.RepositoryOrderFieldCreatedAt
+	}
+
+	err = pager.Each(ctx, func(ctx context.Context, after *githubv4.String) (PageInfo, error) {
+		var q struct {
+			Repository struct {
+				Forks struct {
+					PageInfo PageInfo
+					Nodes    forkNodes
+				} `graphql:"forks(first: $itemsPerBatch, after: $after, orderBy: $order)"`
+			} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
+			RateLimit rateLimit
+		}
 
-	if hasNextPage && isNotDeepEnough {
-		data, err := g.GetPRs(ctx, repoOwner, repoName, endCursor, depth)
+		err := g.Query(ctx, &q, map[string]interface{}{
+			"repositoryOwner": githubv4.String(repoOwner),
+			"repositoryName":  githubv4.String(repoName),
+			"itemsPerBatch":   githubv4.Int(pageSize),
+			"after":           after,
+			"order":           order,
+		})
 		if err != nil {
-			return results, err
+			return PageInfo{}, err
 		}
-		results = append(results, data...)
-	}
 
-	return
-}
-
-// GetForkers gets forkers for the repo
-func (g *GithubFetcher) GetForkers(ctx context.Context, repoOwner string, repoName string, after *githubv4.String,
-	pageSize int) (results []string, err error) {
-	var q struct {
-		Repository struct {
-			Forks struct {
-				PageInfo pageInfo
-				Nodes    forkNodes
-			} `graphql:"forks(first: $itemsPerBatch, after: $after, orderBy: {field: STARGAZERS, direction: DESC})"`
-		} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
-		RateLimit rateLimit
-	}
+		info := q.Repository.Forks.PageInfo
+		for _, fork := range q.Repository.Forks.Nodes {
+			if !since.IsZero() && !fork.CreatedAt.After(since) {
+				info.HasNextPage = false
+				break
+			}
+			results = append(results, Fork{Login: fork.Owner.Login, CreatedAt: fork.CreatedAt.Time})
+		}
 
-	err = g.Query(ctx, &q, map[string]interface{}{
-		"repositoryOwner": githubv4.String(repoOwner),
-		"repositoryName":  githubv4.String(repoName),
-		"itemsPerBatch":   githubv4.Int(pageSize),
-		"after":           after,
+		return info, nil
 	})
-	if err != nil {
-		return
-	}
 
-	for _, owner := range q.Repository.Forks.Nodes {
-		results = append(results, owner.Owner.Login)
-	}
+	return
+}
 
-	if !q.Repository.Forks.PageInfo.HasNextPage {
-		return
-	}
+// GetStargazers gets the users who starred the repo, most recent first. If since is set,
+// only stars given after it are returned.
+// On error it returns the stargazers fetched so far, the paginator's After cursor pointing past them.
+func (g *GithubFetcher) GetStargazers(ctx context.Context, repoOwner string, repoName string, pager *Paginator,
+	pageSize int, since time.Time) (results []Stargazer, err error) {
+	err = pager.Each(ctx, func(ctx context.Context, after *githubv4.String) (PageInfo, error) {
+		var q struct {
+			Repository struct {
+				Stargazers struct {
+					PageInfo PageInfo
+					Edges    stargazerEdges
+				} `graphql:"stargazers(first: $itemsPerBatch, after: $after, orderBy: {field: STARRED_AT, direction: DESC})"`
+			} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
+			RateLimit rateLimit
+		}
 
-	after = &q.Repository.Forks.PageInfo.EndCursor
+		err := g.Query(ctx, &q, map[string]interface{}{
+			"repositoryOwner": githubv4.String(repoOwner),
+			"repositoryName":  githubv4.String(repoName),
+			"itemsPerBatch":   githubv4.Int(pageSize),
+			"after":           after,
+		})
+		if err != nil {
+			return PageInfo{}, err
+		}
 
-	data, err := g.GetForkers(ctx, repoOwner, repoName, after, pageSize)
-	if err != nil {
-		return results, err
-	}
+		info := q.Repository.Stargazers.PageInfo
+		for _, edge := range q.Repository.Stargazers.Edges {
+			if !since.IsZero() && !edge.StarredAt.After(since) {
+				info.HasNextPage = false
+				break
+			}
+			results = append(results, Stargazer{Login: edge.Node.Login, StarredAt: edge.StarredAt.Time})
+		}
 
-	results = append(results, data...)
+		return info, nil
+	})
 
 	return
 }
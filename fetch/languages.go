@@ -0,0 +1,124 @@
+package fetch
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// The weights of a repo's languages in its contributor's profile
+const (
+	OwnedRepoWeight       = 1.0
+	ContributedRepoWeight = 0.5
+)
+
+// LangRepo is a repo's language breakdown
+type LangRepo struct {
+	PrimaryLanguage LangFragment
+	Languages       struct {
+		TotalSize githubv4.Int
+		Edges     []struct {
+			Size githubv4.Int
+			Node LangFragment
+		}
+	} `graphql:"languages(first: 10, orderBy: {field: SIZE, direction: DESC})"`
+}
+
+// LanguageShare is the share of a language in a user's profile
+type LanguageShare struct {
+	Name  string
+	Share float64
+}
+
+// LanguageProfile is a user's weighted language profile, the most used language first
+type LanguageProfile []LanguageShare
+
+// NewLanguageProfile weighs the languages of the user's own and contributed-to repos. Every repo counts
+// as much as its weight, split among its languages by size (or given to its primary language when sizes
+// are missing). The shares add up to 1.
+func NewLanguageProfile(owned, contributed []LangRepo) LanguageProfile {
+	weights := map[string]float64{}
+	add := func(repos []LangRepo, weight float64) {
+		for _, repo := range repos {
+			total := float64(repo.Languages.TotalSize)
+			if total == 0 || len(repo.Languages.Edges) == 0 {
+				if repo.PrimaryLanguage.Name != nil {
+					weights[string(*repo.PrimaryLanguage.Name)] += weight
+				}
+				continue
+			}
+			for _, edge := range repo.Languages.Edges {
+				if edge.Node.Name != nil {
+					weights[string(*edge.Node.Name)] += weight * float64(edge.Size) / total
+				}
+			}
+		}
+	}
+	add(owned, OwnedRepoWeight)
+	add(contributed, ContributedRepoWeight)
+
+	sum := 0.0
+	for _, w := range weights {
+		sum += w
+	}
+
+	profile := make(LanguageProfile, 0, len(weights))
+	for name, w := range weights {
+		profile = append(profile, LanguageShare{Name: name, Share: w / sum})
+	}
+	sort.Slice(profile, func(i, j int) bool {
+		if profile[i].Share != profile[j].Share {
+			return profile[i].Share > profile[j].Share
+		}
+		return profile[i].Name < profile[j].Name
+	})
+
+	return profile
+}
+
+// Top returns the names of the n most used languages
+func (p LanguageProfile) Top(n int) (names []string) {
+	for i := 0; i < n && i < len(p); i++ {
+		names = append(names, p[i].Name)
+	}
+
+	return
+}
+
+// Rank returns the 1 based rank of the language in the profile, or 0 if it's missing
+func (p LanguageProfile) Rank(language string) int {
+	for i, share := range p {
+		if strings.EqualFold(share.Name, language) {
+			return i + 1
+		}
+	}
+
+	return 0
+}
+
+// GetLanguageProfile computes the user's language profile from their own and contributed-to repos
+func (g *GithubFetcher) GetLanguageProfile(ctx context.Context, login string) (LanguageProfile, error) {
+	var q struct {
+		User struct {
+			Repositories struct {
+				Nodes []LangRepo
+			} `graphql:"repositories(first: $maxRepos, ownerAffiliations: OWNER, isFork: false, orderBy: {field: PUSHED_AT, direction: DESC})"`
+			RepositoriesContributedTo struct {
+				Nodes []LangRepo
+			} `graphql:"repositoriesContributedTo(first: $maxRepos, contributionTypes: [COMMIT, PULL_REQUEST], orderBy: {field: PUSHED_AT, direction: DESC})"`
+		} `graphql:"user(login:$login)"`
+		RateLimit rateLimit
+	}
+	vars := map[string]interface{}{
+		"login":    githubv4.String(login),
+		"maxRepos": githubv4.Int(30),
+	}
+
+	if err := g.Query(ctx, &q, vars); err != nil {
+		return nil, err
+	}
+
+	return NewLanguageProfile(q.User.Repositories.Nodes, q.User.RepositoriesContributedTo.Nodes), nil
+}
@@ -0,0 +1,78 @@
+package fetch
+
+import (
+	"context"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// PageInfo is the cursor information github returns for every page of a connection
+type PageInfo struct {
+	EndCursor   githubv4.String
+	HasNextPage githubv4.Boolean
+}
+
+// PageFunc fetches the page that comes after the given cursor (nil meaning the first page)
+// and returns its page info. Returning a PageInfo with HasNextPage set to false stops the walk early.
+type PageFunc func(ctx context.Context, after *githubv4.String) (PageInfo, error)
+
+// Paginator walks a cursor paginated connection page by page, without recursion
+type Paginator struct {
+	// After is the cursor the next page will be fetched after. It is advanced after every
+	// successfully fetched page, so on error it points to the last page that made it.
+	After *githubv4.String
+	// MaxPages limits the number of fetched pages, 0 meaning no limit
+	MaxPages int
+	// OnPage, if set, is called after every successfully fetched page with the new cursor
+	OnPage func(after *githubv4.String)
+
+	pages     int
+	exhausted bool
+}
+
+// NewPaginator returns a paginator starting after the given cursor
+func NewPaginator(after *githubv4.String) *Paginator {
+	return &Paginator{After: after}
+}
+
+// Pages returns how many pages were fetched so far
+func (p *Paginator) Pages() int {
+	return p.pages
+}
+
+// Exhausted tells whether the walk stopped because there were no more pages, as opposed to MaxPages
+// being reached or a failure
+func (p *Paginator) Exhausted() bool {
+	return p.exhausted
+}
+
+// Each calls fetchPage for every page until the connection is exhausted, MaxPages is reached,
+// the context is cancelled or fetchPage fails
+func (p *Paginator) Each(ctx context.Context, fetchPage PageFunc) error {
+	for p.MaxPages == 0 || p.pages < p.MaxPages {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		info, err := fetchPage(ctx, p.After)
+		if err != nil {
+			return err
+		}
+		p.pages++
+
+		if info.EndCursor != "" {
+			cursor := info.EndCursor
+			p.After = &cursor
+		}
+		if p.OnPage != nil {
+			p.OnPage(p.After)
+		}
+
+		if !info.HasNextPage {
+			p.exhausted = true
+			return nil
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package fetch
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// ProfileRepo is one of the user's own repositories
+type ProfileRepo struct {
+	NameWithOwner   githubv4.String
+	URL             githubv4.URI
+	PrimaryLanguage struct {
+		Name githubv4.String
+	}
+	Stargazers struct {
+		TotalCount githubv4.Int
+	}
+}
+
+// Profile is a user enriched with their recent activity and top repositories
+type Profile struct {
+	User
+	Activity
+	Repositories struct {
+		Nodes []ProfileRepo
+	} `graphql:"repositories(first: $maxRepos, ownerAffiliations: OWNER, isFork: false, orderBy: {field: STARGAZERS, direction: DESC})"`
+}
+
+// ProfileCsvHeader is the header matching Profile.FormatForCsv
+var ProfileCsvHeader = append(append(append([]string{}, UserCsvHeader...), ActivityCsvHeader...),
+	"Organisation names", "Top repositories", "Languages")
+
+// OrganizationNames returns the logins of the organizations the user is a member of
+func (p Profile) OrganizationNames() (names []string) {
+	for _, org := range p.Organizations.Nodes {
+		names = append(names, string(org.Login))
+	}
+
+	return
+}
+
+// Languages returns the primary languages of the user's top repositories, the most used first
+func (p Profile) Languages() []string {
+	counts := map[string]int{}
+	for _, repo := range p.Repositories.Nodes {
+		if lang := string(repo.PrimaryLanguage.Name); lang != "" {
+			counts[lang]++
+		}
+	}
+
+	langs := make([]string, 0, len(counts))
+	for lang := range counts {
+		langs = append(langs, lang)
+	}
+	sort.Slice(langs, func(i, j int) bool {
+		if counts[langs[i]] != counts[langs[j]] {
+			return counts[langs[i]] > counts[langs[j]]
+		}
+		return langs[i] < langs[j]
+	})
+
+	return langs
+}
+
+// FormatForCsv returns the user's columns followed by their activity and the enrichment ones
+func (p Profile) FormatForCsv() []string {
+	var repos []string
+	for _, repo := range p.Repositories.Nodes {
+		repos = append(repos, string(repo.NameWithOwner))
+	}
+
+	return append(append(p.User.FormatForCsv(), p.Activity.FormatForCsv()...),
+		strings.Join(p.OrganizationNames(), " "),
+		strings.Join(repos, " "),
+		strings.Join(p.Languages(), " "),
+	)
+}
+
+// GetProfile retrieves a gh user together with their top repos and organizations
+func (g *GithubFetcher) GetProfile(ctx context.Context, login string) (Profile, error) {
+	var q struct {
+		User      Profile `graphql:"user(login:$login)"`
+		RateLimit rateLimit
+	}
+	vars := map[string]interface{}{
+		"login":    githubv4.String(login),
+		"maxOrgs":  githubv4.Int(MaxOrgs),
+		"maxRepos": githubv4.Int(10),
+	}
+
+	if err := g.Query(ctx, &q, vars); err != nil {
+		return Profile{}, err
+	}
+
+	return q.User, nil
+}
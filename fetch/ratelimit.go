@@ -0,0 +1,25 @@
+package fetch
+
+import (
+	"context"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// RateLimit is the state of the token's graphql rate limit
+type RateLimit struct {
+	Limit     githubv4.Int
+	Cost      githubv4.Int
+	Remaining githubv4.Int
+	ResetAt   githubv4.DateTime
+}
+
+// GetRateLimit fetches the token's rate limit, bypassing the cache
+func (g *GithubFetcher) GetRateLimit(ctx context.Context) (RateLimit, error) {
+	var q struct {
+		RateLimit RateLimit
+	}
+	err := g.Client.Query(ctx, &q, nil)
+
+	return q.RateLimit, err
+}
@@ -0,0 +1,123 @@
+package fetch
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// Repo is a repository's summary, as listed for an owner or found by a search
+type Repo struct {
+	Name  githubv4.String
+	Owner struct {
+		Login githubv4.String
+	}
+	Description     githubv4.String
+	URL             githubv4.URI
+	PrimaryLanguage struct {
+		Name githubv4.String
+	}
+	Stargazers struct {
+		TotalCount githubv4.Int
+	}
+	ForkCount  githubv4.Int
+	IsArchived githubv4.Boolean
+	IsFork     githubv4.Boolean
+	PushedAt   githubv4.DateTime
+}
+
+// NameWithOwner returns the repo's owner/name
+func (r Repo) NameWithOwner() string {
+	return string(r.Owner.Login) + "/" + string(r.Name)
+}
+
+// Score ranks the repo as a recruiting source: its stars and forks, decaying with the time since it was last pushed to
+func (r Repo) Score(now time.Time) float64 {
+	popularity := float64(r.Stargazers.TotalCount) + 2*float64(r.ForkCount)
+	idleYears := now.Sub(r.PushedAt.Time).Hours() / 24 / 365
+	if idleYears < 0 {
+		idleYears = 0
+	}
+
+	return popularity / (1 + idleYears)
+}
+
+// RankRepos sorts the repos by score, best first
+func RankRepos(repos []Repo, now time.Time) {
+	sort.SliceStable(repos, func(i, j int) bool {
+		return repos[i].Score(now) > repos[j].Score(now)
+	})
+}
+
+// RepoFilter selects repos by their summary. Zero values don't filter.
+type RepoFilter struct {
+	Language     string
+	MinStars     int
+	Archived     bool // include archived repos
+	Forks        bool // include forks
+	PushedWithin time.Duration
+}
+
+// Match tells whether the repo passes the filter
+func (f RepoFilter) Match(r Repo) bool {
+	if f.Language != "" && !strings.EqualFold(f.Language, string(r.PrimaryLanguage.Name)) {
+		return false
+	}
+	if int(r.Stargazers.TotalCount) < f.MinStars {
+		return false
+	}
+	if bool(r.IsArchived) && !f.Archived {
+		return false
+	}
+	if bool(r.IsFork) && !f.Forks {
+		return false
+	}
+	if f.PushedWithin > 0 && time.Since(r.PushedAt.Time) > f.PushedWithin {
+		return false
+	}
+
+	return true
+}
+
+// GetOrgRepos lists the organization's public repos that pass the filter, most starred first
+func (g *GithubFetcher) GetOrgRepos(ctx context.Context, login string, pager *Paginator, filter RepoFilter) (
+	results []Repo, err error) {
+	err = pager.Each(ctx, func(ctx context.Context, after *githubv4.String) (PageInfo, error) {
+		var q struct {
+			Organization struct {
+				Repositories struct {
+					PageInfo PageInfo
+					Nodes    []Repo
+				} `graphql:"repositories(first: $itemsPerBatch, after: $after, privacy: PUBLIC, orderBy: {field: STARGAZERS, direction: DESC})"`
+			} `graphql:"organization(login:$login)"`
+			RateLimit rateLimit
+		}
+
+		err := g.Query(ctx, &q, map[string]interface{}{
+			"login":         githubv4.String(login),
+			"itemsPerBatch": githubv4.Int(100),
+			"after":         after,
+		})
+		if err != nil {
+			return PageInfo{}, err
+		}
+
+		info := q.Organization.Repositories.PageInfo
+		for _, repo := range q.Organization.Repositories.Nodes {
+			if int(repo.Stargazers.TotalCount) < filter.MinStars {
+				info.HasNextPage = false // sorted by stars, so the rest have even less
+				break
+			}
+			if filter.Match(repo) {
+				results = append(results, repo)
+			}
+		}
+
+		return info, nil
+	})
+
+	return
+}
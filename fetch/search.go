@@ -0,0 +1,238 @@
+package fetch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	log "github.com/sirupsen/logrus"
+)
+
+// SearchCap is the maximum number of results github returns for a search, no matter the pagination
+const SearchCap = 1000
+
+// searchDateFormat is the date format of search qualifiers
+const searchDateFormat = "2006-01-02"
+
+// githubLaunch is when the oldest accounts were created, the default start of the created range
+var githubLaunch = time.Date(2008, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// UserSearch holds the structured criteria of a user search
+type UserSearch struct {
+	Location string
+	Language string
+	// Followers and Repos take search ranges, like ">50", "10..100" or "<=5"
+	Followers   string
+	Repos       string
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+}
+
+// Query compiles the criteria into a github search query string
+func (s UserSearch) Query() string {
+	parts := []string{"type:user"}
+	if s.Location != "" {
+		parts = append(parts, "location:"+quoteQualifier(s.Location))
+	}
+	if s.Language != "" {
+		parts = append(parts, "language:"+quoteQualifier(s.Language))
+	}
+	if s.Followers != "" {
+		parts = append(parts, "followers:"+s.Followers)
+	}
+	if s.Repos != "" {
+		parts = append(parts, "repos:"+s.Repos)
+	}
+	if !s.CreatedFrom.IsZero() || !s.CreatedTo.IsZero() {
+		from, to := s.createdRange()
+		parts = append(parts, fmt.Sprintf("created:%s..%s", from.Format(searchDateFormat), to.Format(searchDateFormat)))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func quoteQualifier(value string) string {
+	if strings.ContainsAny(value, " \t") {
+		return fmt.Sprintf("%q", value)
+	}
+
+	return value
+}
+
+// createdRange returns the created range, defaulting to everything up to today
+func (s UserSearch) createdRange() (from, to time.Time) {
+	from, to = s.CreatedFrom, s.CreatedTo
+	if from.IsZero() {
+		from = githubLaunch
+	}
+	if to.IsZero() {
+		to = time.Now().UTC()
+	}
+
+	return from.Truncate(24 * time.Hour), to.Truncate(24 * time.Hour)
+}
+
+// Split halves the search's created range. It returns false if the range is a single day already.
+func (s UserSearch) Split() (UserSearch, UserSearch, bool) {
+	from, to := s.createdRange()
+	days := int(to.Sub(from).Hours() / 24)
+	if days < 1 {
+		return s, s, false
+	}
+
+	left, right := s, s
+	left.CreatedFrom, left.CreatedTo = from, from.AddDate(0, 0, days/2)
+	right.CreatedFrom, right.CreatedTo = left.CreatedTo.AddDate(0, 0, 1), to
+
+	return left, right, true
+}
+
+// CountUsers returns how many users match the search query
+func (g *GithubFetcher) CountUsers(ctx context.Context, query string) (int, error) {
+	var q struct {
+		Search struct {
+			UserCount githubv4.Int
+		} `graphql:"search(query: $query, type: USER, first: 1)"`
+		RateLimit rateLimit
+	}
+
+	if err := g.Query(ctx, &q, map[string]interface{}{"query": githubv4.String(query)}); err != nil {
+		return 0, err
+	}
+
+	return int(q.Search.UserCount), nil
+}
+
+// SearchUsers returns the users matching the search query, up to the search cap
+func (g *GithubFetcher) SearchUsers(ctx context.Context, query string, pager *Paginator) (results []User, err error) {
+	err = pager.Each(ctx, func(ctx context.Context, after *githubv4.String) (PageInfo, error) {
+		var q struct {
+			Search struct {
+				PageInfo PageInfo
+				Nodes    []struct {
+					User User `graphql:"... on User"`
+				}
+			} `graphql:"search(query: $query, type: USER, first: $itemsPerBatch, after: $after)"`
+			RateLimit rateLimit
+		}
+
+		err := g.Query(ctx, &q, map[string]interface{}{
+			"query":         githubv4.String(query),
+			"itemsPerBatch": githubv4.Int(SearchPageSize),
+			"maxOrgs":       githubv4.Int(MaxOrgs),
+			"after":         after,
+		})
+		if err != nil {
+			return PageInfo{}, err
+		}
+
+		for _, node := range q.Search.Nodes {
+			if node.User.Login != "" { // organizations match too, but come back empty
+				results = append(results, node.User)
+			}
+		}
+
+		return q.Search.PageInfo, nil
+	})
+
+	return
+}
+
+// SearchAllUsers returns all the users matching the search, slicing its created range
+// until every slice fits under the search cap. A single day over the cap can't be sliced,
+// only its first SearchCap users being returned.
+func (g *GithubFetcher) SearchAllUsers(ctx context.Context, search UserSearch) ([]User, error) {
+	count, err := g.CountUsers(ctx, search.Query())
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, nil
+	}
+
+	if count > SearchCap {
+		if left, right, ok := search.Split(); ok {
+			results, err := g.SearchAllUsers(ctx, left)
+			if err != nil {
+				return results, err
+			}
+			more, err := g.SearchAllUsers(ctx, right)
+
+			return append(results, more...), err
+		}
+		day, _ := search.createdRange()
+		log.WithFields(log.Fields{
+			"query": search.Query(),
+			"day":   day.Format(searchDateFormat),
+			"users": count,
+		}).Warnf("too many users registered the same day, only the first %d are searched", SearchCap)
+	}
+
+	return g.SearchUsers(ctx, search.Query(), NewPaginator(nil))
+}
+
+// RepoSearch holds the structured criteria of a repository search
+type RepoSearch struct {
+	Topics   []string
+	Language string
+	MinStars int
+	// Archived includes archived repos, which are skipped by default
+	Archived bool
+}
+
+// Query compiles the criteria into a github search query string
+func (s RepoSearch) Query() string {
+	var parts []string
+	for _, topic := range s.Topics {
+		parts = append(parts, "topic:"+quoteQualifier(topic))
+	}
+	if s.Language != "" {
+		parts = append(parts, "language:"+quoteQualifier(s.Language))
+	}
+	if s.MinStars > 0 {
+		parts = append(parts, fmt.Sprintf("stars:>=%d", s.MinStars))
+	}
+	if !s.Archived {
+		parts = append(parts, "archived:false")
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// SearchPageSize is how many results each page of a repo search has
+const SearchPageSize = 100
+
+// SearchRepos returns the repositories matching the search query, walking as many pages as the paginator allows,
+// up to the search cap
+func (g *GithubFetcher) SearchRepos(ctx context.Context, query string, pager *Paginator) (results []Repo, err error) {
+	err = pager.Each(ctx, func(ctx context.Context, after *githubv4.String) (PageInfo, error) {
+		var q struct {
+			Search struct {
+				PageInfo PageInfo
+				Nodes    []struct {
+					Repo Repo `graphql:"... on Repository"`
+				}
+			} `graphql:"search(query: $query, type: REPOSITORY, first: $itemsPerBatch, after: $after)"`
+			RateLimit rateLimit
+		}
+
+		err := g.Query(ctx, &q, map[string]interface{}{
+			"query":         githubv4.String(query),
+			"itemsPerBatch": githubv4.Int(SearchPageSize),
+			"after":         after,
+		})
+		if err != nil {
+			return PageInfo{}, err
+		}
+
+		for _, node := range q.Search.Nodes {
+			results = append(results, node.Repo)
+		}
+
+		return q.Search.PageInfo, nil
+	})
+
+	return
+}
@@ -0,0 +1,77 @@
+package fetch
+
+import (
+	"reflect"
+	"sync/atomic"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// Stats counts a fetcher's queries. It's safe for concurrent use.
+type Stats struct {
+	// Queries were sent to the api
+	Queries int64
+	// CacheHits were answered by the cache
+	CacheHits int64
+	// Cost is the rate limit points the queries cost
+	Cost int64
+}
+
+// Snapshot returns a copy of the counters
+func (s *Stats) Snapshot() Stats {
+	return Stats{
+		Queries:   atomic.LoadInt64(&s.Queries),
+		CacheHits: atomic.LoadInt64(&s.CacheHits),
+		Cost:      atomic.LoadInt64(&s.Cost),
+	}
+}
+
+// Reset zeroes the counters
+func (s *Stats) Reset() {
+	atomic.StoreInt64(&s.Queries, 0)
+	atomic.StoreInt64(&s.CacheHits, 0)
+	atomic.StoreInt64(&s.Cost, 0)
+}
+
+func (s *Stats) cacheHit() {
+	if s != nil {
+		atomic.AddInt64(&s.CacheHits, 1)
+	}
+}
+
+func (s *Stats) query(q interface{}) {
+	if s != nil {
+		atomic.AddInt64(&s.Queries, 1)
+		atomic.AddInt64(&s.Cost, queryCost(q))
+	}
+}
+
+// queryCost returns the cost the api reported in the query's RateLimit field, if it has one
+func queryCost(q interface{}) int64 {
+	v := reflect.ValueOf(q)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return 0
+	}
+	rl := v.FieldByName("RateLimit")
+	for rl.Kind() == reflect.Ptr && !rl.IsNil() {
+		rl = rl.Elem()
+	}
+	if rl.Kind() != reflect.Struct {
+		return 0
+	}
+	cost := rl.FieldByName("Cost")
+	for cost.Kind() == reflect.Ptr && !cost.IsNil() {
+		cost = cost.Elem()
+	}
+	if !cost.IsValid() {
+		return 0
+	}
+	if c, ok := cost.Interface().(githubv4.Int); ok {
+		return int64(c)
+	}
+
+	return 0
+}
@@ -2,6 +2,7 @@ package fetch
 
 import (
 	"strconv"
+	"time"
 
 	"github.com/shurcooL/githubv4"
 )
@@ -25,6 +26,15 @@ type LangFragment struct {
 	Name *githubv4.String
 }
 
+// MaxOrgs is how many of a user's organizations are fetched along with them
+const MaxOrgs = 20
+
+// Organization is an organization a user is a member of
+type Organization struct {
+	Login githubv4.String
+	Name  githubv4.String
+}
+
 // User represents a gh user's interesting data
 type User struct {
 	ID        githubv4.ID
@@ -43,13 +53,7 @@ type User struct {
 	}
 	Organizations struct {
 		TotalCount githubv4.Int
-		// Nodes      []struct {
-		// 	ID          githubv4.ID
-		// 	Login       githubv4.String
-		// 	Email       *githubv4.String
-		// 	WebsiteUrl  *githubv4.URI
-		// 	Description githubv4.String
-		// }
+		Nodes      []Organization
 	} `graphql:"organizations(first: $maxOrgs)"`
 	IsBountyHunter githubv4.Boolean
 	IsCampusExpert githubv4.Boolean
@@ -58,6 +62,21 @@ type User struct {
 	IsHireable     githubv4.Boolean
 }
 
+// UserCsvHeader is the header matching User.FormatForCsv
+var UserCsvHeader = []string{
+	"Login",
+	"Location",
+	"Email",
+	"Name",
+	"Company",
+	"Bio",
+	"Registered",
+	"Followers",
+	"Following",
+	"Organisations",
+	"Hireable",
+}
+
 // FormatForCsv returns a []string representation for the full user
 func (u User) FormatForCsv() (result []string) {
 	result = []string{
@@ -110,21 +129,21 @@ type prCommit struct {
 
 type pr struct {
 	Commits struct {
-		PageInfo pageInfo
+		PageInfo PageInfo
 		Nodes    []prCommit
 	} `graphql:"commits(first: $prCommitsPerBatch)"`
 	Comments struct {
-		PageInfo pageInfo
+		PageInfo PageInfo
 		Nodes    []prComment
 	} `graphql:"comments(first: $prCommentsPerBatch)"`
 	Reviews struct {
-		PageInfo pageInfo
+		PageInfo PageInfo
 		Nodes    []prReview
 	} `graphql:"reviews(first: $prReviewsPerBatch)"`
 }
 
 type prs struct {
-	PageInfo pageInfo
+	PageInfo PageInfo
 	Nodes    []pr
 }
 
@@ -175,27 +194,46 @@ type UserFetchResult struct {
 	Err   error
 }
 
-type pageInfo struct {
-	EndCursor   githubv4.String
-	HasNextPage githubv4.Boolean
+type forkNodes []struct {
+	CreatedAt githubv4.DateTime
+	Owner     struct {
+		Login string
+	}
 }
 
-type forkNodes []struct {
-	Owner struct {
+type stargazerEdges []struct {
+	StarredAt githubv4.DateTime
+	Node      struct {
 		Login string
 	}
 }
 
+// Fork is a fork's owner together with the time the fork was created
+type Fork struct {
+	Login     string
+	CreatedAt time.Time
+}
+
+// Stargazer is a user who starred a repo together with the time they did it
+type Stargazer struct {
+	Login     string
+	StarredAt time.Time
+}
+
 // PrWithData represents the PR and its data
 type PrWithData struct {
-	URL      githubv4.URI
-	Title    githubv4.String
+	URL       githubv4.URI
+	Title     githubv4.String
+	UpdatedAt githubv4.DateTime
+	Author    struct {
+		Login githubv4.String
+	}
 	Comments struct {
 		Nodes []prComment
 	} `graphql:"comments(first: $prItemsPerBatch)"`
 	Reviews struct {
 		Nodes []prReview
-	} `graphql:"comments(first: $prItemsPerBatch)"`
+	} `graphql:"reviews(first: $prItemsPerBatch)"`
 	Commits struct {
 		Nodes []prCommit
 	} `graphql:"commits(first: $prCommitsPerBatch)"`
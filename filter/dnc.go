@@ -0,0 +1,34 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/state"
+)
+
+// DoNotContact rejects the people on the do-not-contact list
+type DoNotContact struct {
+	List *state.DoNotContact
+}
+
+// Match implements Filter
+func (d DoNotContact) Match(ctx context.Context, c *candidate.Candidate) (bool, string) {
+	id, _ := c.User.ID.(string)
+	e, found := d.List.Lookup(c.Login(), id)
+	if !found {
+		return true, ""
+	}
+
+	reason := "on the do-not-contact list"
+	if !strings.EqualFold(e.Login, c.Login()) {
+		reason = fmt.Sprintf("%s as %s", reason, e.Login)
+	}
+	if e.Reason != "" {
+		reason = fmt.Sprintf("%s: %s", reason, e.Reason)
+	}
+
+	return false, reason
+}
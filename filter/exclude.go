@@ -0,0 +1,75 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/pkg/errors"
+)
+
+// Exclusion rejects the members of some organizations and the employees of some companies.
+// Only the public organization memberships fetched with the user can be checked.
+type Exclusion struct {
+	orgs      map[string]bool
+	companies []*regexp.Regexp
+}
+
+// NewExclusion compiles the excluded organization logins and company name patterns,
+// the latter being case insensitive regular expressions
+func NewExclusion(orgs, companyPatterns []string) (*Exclusion, error) {
+	e := &Exclusion{orgs: map[string]bool{}}
+	for _, org := range orgs {
+		e.orgs[strings.ToLower(strings.TrimPrefix(org, "@"))] = true
+	}
+	for _, pattern := range companyPatterns {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid company pattern %q", pattern)
+		}
+		e.companies = append(e.companies, re)
+	}
+
+	return e, nil
+}
+
+// Empty tells whether the exclusion doesn't exclude anyone
+func (e *Exclusion) Empty() bool {
+	return len(e.orgs) == 0 && len(e.companies) == 0
+}
+
+// Match implements Filter
+func (e *Exclusion) Match(ctx context.Context, c *candidate.Candidate) (bool, string) {
+	for _, org := range c.User.Organizations.Nodes {
+		if e.orgs[strings.ToLower(string(org.Login))] {
+			return false, fmt.Sprintf("member of the excluded organization %s", org.Login)
+		}
+	}
+
+	company := string(c.User.Company)
+	for _, re := range e.companies {
+		if company != "" && re.MatchString(company) {
+			return false, fmt.Sprintf("company %q matches the excluded %q", company, re.String()[len("(?i)"):])
+		}
+	}
+
+	return true, ""
+}
+
+// Audited passes the candidates rejected by its filter to Audit before rejecting them
+type Audited struct {
+	Filter
+	Audit func(c *candidate.Candidate, reason string)
+}
+
+// Match implements Filter
+func (a Audited) Match(ctx context.Context, c *candidate.Candidate) (bool, string) {
+	ok, reason := a.Filter.Match(ctx, c)
+	if !ok {
+		a.Audit(c, reason)
+	}
+
+	return ok, reason
+}
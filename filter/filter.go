@@ -0,0 +1,175 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/fetch"
+	"github.com/florinutz/gh-recruiter/timezone"
+)
+
+// Filter decides whether a candidate should be reported. Filters may enrich the candidate
+// with the data they needed to decide. When a candidate is rejected, the reason says why.
+type Filter interface {
+	Match(ctx context.Context, c *candidate.Candidate) (ok bool, reason string)
+}
+
+// Chain is a list of filters a candidate has to pass, checked in order
+type Chain []Filter
+
+// Match runs the candidate through the filters, stopping at the first one rejecting it
+func (ch Chain) Match(ctx context.Context, c *candidate.Candidate) (bool, string) {
+	for _, f := range ch {
+		if ok, reason := f.Match(ctx, c); !ok {
+			return false, reason
+		}
+	}
+
+	return true, ""
+}
+
+// DefaultLocations are the location keywords matched when none are configured
+var DefaultLocations = []string{"germany", "deutschland", "poland", "berlin", "hamburg", "hanover", "leipzig",
+	"dresden"}
+
+// Location matches the candidates whose location contains any of the keywords
+type Location struct {
+	Keywords []string
+	// CommitTimes, when set, is used for the candidates having no location: the timezone inferred
+	// from their commit times has to be one of Offsets, with at least MinConfidence
+	CommitTimes   func(ctx context.Context, c *candidate.Candidate) ([]time.Time, error)
+	Offsets       []int
+	MinConfidence float64
+}
+
+// Match implements Filter
+func (l Location) Match(ctx context.Context, c *candidate.Candidate) (bool, string) {
+	if LocationMatches(string(c.User.Location), l.Keywords) {
+		return true, ""
+	}
+	if strings.TrimSpace(string(c.User.Location)) != "" || l.CommitTimes == nil {
+		return false, fmt.Sprintf("location %q is not interesting", c.User.Location)
+	}
+
+	if c.Timezone == nil {
+		times, err := l.CommitTimes(ctx, c)
+		if err != nil {
+			return false, fmt.Sprintf("no location and couldn't fetch commit times: %s", err)
+		}
+		estimate, ok := timezone.Infer(times)
+		if !ok {
+			return false, "no location and no commits to infer a timezone from"
+		}
+		c.Timezone = &estimate
+	}
+
+	if c.Timezone.Confidence < l.MinConfidence {
+		return false, fmt.Sprintf("no location and the inferred timezone %s is not certain enough", c.Timezone)
+	}
+	for _, offset := range l.Offsets {
+		if c.Timezone.Offset == offset {
+			return true, ""
+		}
+	}
+
+	return false, fmt.Sprintf("no location and the inferred timezone %s is not interesting", c.Timezone)
+}
+
+// LocationMatches tells whether the location contains any of the keywords
+func LocationMatches(location string, keywords []string) bool {
+	lowerLocation := strings.ToLower(location)
+	for _, keyword := range keywords {
+		if strings.Contains(lowerLocation, strings.ToLower(keyword)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Languages enriches the candidates with their language profile. If Require is set,
+// only the candidates having it among their Top languages match.
+type Languages struct {
+	Fetch   func(ctx context.Context, login string) (fetch.LanguageProfile, error)
+	Require string
+	Top     int
+}
+
+// Match implements Filter
+func (l Languages) Match(ctx context.Context, c *candidate.Candidate) (bool, string) {
+	if c.Languages == nil {
+		profile, err := l.Fetch(ctx, c.Login())
+		if err != nil {
+			if l.Require == "" {
+				return true, "" // the profile is just nice to have
+			}
+			return false, fmt.Sprintf("couldn't fetch the language profile: %s", err)
+		}
+		c.Languages = profile
+	}
+
+	if l.Require == "" {
+		return true, ""
+	}
+	if rank := c.Languages.Rank(l.Require); rank == 0 || (l.Top > 0 && rank > l.Top) {
+		return false, fmt.Sprintf("%s is not among the top %d languages (%s)", l.Require, l.Top,
+			strings.Join(c.Languages.Top(l.Top), ", "))
+	}
+
+	return true, ""
+}
+
+// Activity matches the candidates who were active recently enough and contributed enough over the last year,
+// fetching their activity when it's missing
+type Activity struct {
+	Fetch func(ctx context.Context, login string) (fetch.Activity, error)
+	// Within is the maximum time since the candidate was last active, 0 meaning any
+	Within           time.Duration
+	MinContributions int
+	Now              func() time.Time
+}
+
+// Match implements Filter
+func (a Activity) Match(ctx context.Context, c *candidate.Candidate) (bool, string) {
+	if c.Activity == nil {
+		activity, err := a.Fetch(ctx, c.Login())
+		if err != nil {
+			return false, fmt.Sprintf("couldn't fetch the activity: %s", err)
+		}
+		c.Activity = &activity
+	}
+
+	if total := c.Activity.Contributions.Total(); total < a.MinContributions {
+		return false, fmt.Sprintf("only %d contributions over the last year", total)
+	}
+
+	if a.Within > 0 {
+		last := c.Activity.LastActive()
+		if last.IsZero() {
+			return false, "never active"
+		}
+		if idle := a.Now().Sub(last); idle > a.Within {
+			return false, fmt.Sprintf("last active on %s", last.Format("02-Jan-2006"))
+		}
+	}
+
+	return true, ""
+}
+
+// MinScore matches the candidates scoring at least Score
+type MinScore struct {
+	Score float64
+	Now   func() time.Time
+}
+
+// Match implements Filter
+func (m MinScore) Match(ctx context.Context, c *candidate.Candidate) (bool, string) {
+	if score := c.Score(m.Now()); score < m.Score {
+		return false, fmt.Sprintf("scored only %.2f", score)
+	}
+
+	return true, ""
+}
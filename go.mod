@@ -1,13 +1,16 @@
 module github.com/florinutz/gh-recruiter
 
+go 1.16
+
 require (
 	github.com/BurntSushi/toml v0.3.1 // indirect
 	github.com/birkelund/boltdbcache v0.0.0-20171002130706-d9be082dca00
 	github.com/boltdb/bolt v1.3.1 // indirect
-	github.com/coreos/bbolt v1.3.0 // indirect
+	github.com/coreos/bbolt v1.3.0
 	github.com/gregjones/httpcache v0.0.0-20181110185634-c63ab54fda8f
 	github.com/inconshreveable/mousetrap v1.0.0 // indirect
 	github.com/mitchellh/go-homedir v1.0.0
+	github.com/mitchellh/mapstructure v1.0.0
 	github.com/pelletier/go-toml v1.2.0
 	github.com/pkg/errors v0.8.0
 	github.com/shurcooL/githubv4 v0.0.0-20181111053151-5851091a7645
@@ -0,0 +1,147 @@
+package graph
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Metrics are a user's centrality metrics
+type Metrics struct {
+	Degree   int
+	PageRank float64
+}
+
+// Metrics computes the centrality metrics of every user
+func (g *Graph) Metrics() map[string]Metrics {
+	degree := g.Degree()
+	rank := g.PageRank(0.85, 50)
+
+	metrics := make(map[string]Metrics, len(degree))
+	for node := range g.nodes {
+		metrics[node] = Metrics{Degree: degree[node], PageRank: rank[node]}
+	}
+
+	return metrics
+}
+
+// WriteJSON writes the graph's nodes, with their metrics, and edges as json
+func (g *Graph) WriteJSON(w io.Writer) error {
+	type node struct {
+		ID string
+		Metrics
+	}
+	var doc struct {
+		Nodes []node
+		Edges []Edge
+	}
+
+	metrics := g.Metrics()
+	for _, n := range g.Nodes() {
+		doc.Nodes = append(doc.Nodes, node{ID: n, Metrics: metrics[n]})
+	}
+	doc.Edges = g.Edges()
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(doc)
+}
+
+// WriteDOT writes the graph in graphviz's dot language
+func (g *Graph) WriteDOT(w io.Writer) error {
+	if _, err := fmt.Fprintln(w, "digraph collaboration {"); err != nil {
+		return err
+	}
+	for _, n := range g.Nodes() {
+		if _, err := fmt.Fprintf(w, "  %q;\n", n); err != nil {
+			return err
+		}
+	}
+	for _, e := range g.Edges() {
+		_, err := fmt.Fprintf(w, "  %q -> %q [label=%q, weight=%d, penwidth=%d];\n",
+			e.From, e.To, e.Kind, e.Weight, e.Weight)
+		if err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(w, "}")
+
+	return err
+}
+
+type graphmlKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type graphmlData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+type graphmlNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphmlData `xml:"data"`
+}
+
+type graphmlEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphmlData `xml:"data"`
+}
+
+type graphmlDoc struct {
+	XMLName xml.Name     `xml:"graphml"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Keys    []graphmlKey `xml:"key"`
+	Graph   struct {
+		EdgeDefault string        `xml:"edgedefault,attr"`
+		Nodes       []graphmlNode `xml:"node"`
+		Edges       []graphmlEdge `xml:"edge"`
+	} `xml:"graph"`
+}
+
+// WriteGraphML writes the graph as GraphML, with the metrics as node attributes
+func (g *Graph) WriteGraphML(w io.Writer) error {
+	doc := graphmlDoc{
+		Xmlns: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphmlKey{
+			{ID: "degree", For: "node", Name: "degree", Type: "int"},
+			{ID: "pagerank", For: "node", Name: "pagerank", Type: "double"},
+			{ID: "kind", For: "edge", Name: "kind", Type: "string"},
+			{ID: "weight", For: "edge", Name: "weight", Type: "int"},
+		},
+	}
+	doc.Graph.EdgeDefault = "directed"
+
+	metrics := g.Metrics()
+	for _, n := range g.Nodes() {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphmlNode{ID: n, Data: []graphmlData{
+			{Key: "degree", Value: strconv.Itoa(metrics[n].Degree)},
+			{Key: "pagerank", Value: strconv.FormatFloat(metrics[n].PageRank, 'f', 6, 64)},
+		}})
+	}
+	for _, e := range g.Edges() {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphmlEdge{Source: e.From, Target: e.To, Data: []graphmlData{
+			{Key: "kind", Value: string(e.Kind)},
+			{Key: "weight", Value: strconv.Itoa(e.Weight)},
+		}})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
@@ -0,0 +1,159 @@
+package graph
+
+import (
+	"sort"
+)
+
+// Kind is the kind of relationship an edge stands for
+type Kind string
+
+// The relationships between PR authors and the users involved in their PRs
+const (
+	Reviewed  Kind = "reviewed"
+	Commented Kind = "commented"
+)
+
+// Edge is a weighted, directed relationship, e.g. From reviewed To's PRs Weight times
+type Edge struct {
+	From   string
+	To     string
+	Kind   Kind
+	Weight int
+}
+
+type edgeKey struct {
+	from, to string
+	kind     Kind
+}
+
+// Graph is a weighted directed graph of users
+type Graph struct {
+	nodes map[string]bool
+	edges map[edgeKey]int
+}
+
+// New returns an empty graph
+func New() *Graph {
+	return &Graph{nodes: map[string]bool{}, edges: map[edgeKey]int{}}
+}
+
+// Add adds one to the weight of the from - to relationship. Self loops and anonymous users are ignored.
+func (g *Graph) Add(from, to string, kind Kind) {
+	g.addWeight(from, to, kind, 1)
+}
+
+func (g *Graph) addWeight(from, to string, kind Kind, weight int) {
+	if from == "" || to == "" || from == to {
+		return
+	}
+	g.nodes[from], g.nodes[to] = true, true
+	g.edges[edgeKey{from, to, kind}] += weight
+}
+
+// Merge adds all of other's edges to the graph
+func (g *Graph) Merge(other *Graph) {
+	for key, weight := range other.edges {
+		g.addWeight(key.from, key.to, key.kind, weight)
+	}
+}
+
+// Remove removes the user and all their relationships
+func (g *Graph) Remove(node string) {
+	delete(g.nodes, node)
+	for key := range g.edges {
+		if key.from == node || key.to == node {
+			delete(g.edges, key)
+		}
+	}
+}
+
+// Nodes returns the graph's users, sorted
+func (g *Graph) Nodes() []string {
+	nodes := make([]string, 0, len(g.nodes))
+	for node := range g.nodes {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	return nodes
+}
+
+// Edges returns the graph's edges, sorted by their ends
+func (g *Graph) Edges() []Edge {
+	edges := make([]Edge, 0, len(g.edges))
+	for key, weight := range g.edges {
+		edges = append(edges, Edge{From: key.from, To: key.to, Kind: key.kind, Weight: weight})
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		if edges[i].To != edges[j].To {
+			return edges[i].To < edges[j].To
+		}
+		return edges[i].Kind < edges[j].Kind
+	})
+
+	return edges
+}
+
+// Degree returns the number of distinct users each user is connected to, in either direction
+func (g *Graph) Degree() map[string]int {
+	neighbours := map[string]map[string]bool{}
+	for key := range g.edges {
+		for _, pair := range [][2]string{{key.from, key.to}, {key.to, key.from}} {
+			if neighbours[pair[0]] == nil {
+				neighbours[pair[0]] = map[string]bool{}
+			}
+			neighbours[pair[0]][pair[1]] = true
+		}
+	}
+
+	degree := make(map[string]int, len(g.nodes))
+	for node := range g.nodes {
+		degree[node] = len(neighbours[node])
+	}
+
+	return degree
+}
+
+// PageRank computes the weighted PageRank of every user, rank flowing along the edges
+// (towards the authors whose PRs got reviewed or commented on). Ranks add up to 1.
+func (g *Graph) PageRank(damping float64, iterations int) map[string]float64 {
+	nodes := g.Nodes()
+	n := float64(len(nodes))
+	if n == 0 {
+		return map[string]float64{}
+	}
+
+	outWeight := map[string]float64{}
+	for key, weight := range g.edges {
+		outWeight[key.from] += float64(weight)
+	}
+
+	rank := make(map[string]float64, len(nodes))
+	for _, node := range nodes {
+		rank[node] = 1 / n
+	}
+
+	for i := 0; i < iterations; i++ {
+		// rank of the users without outgoing edges is spread evenly
+		dangling := 0.0
+		for _, node := range nodes {
+			if outWeight[node] == 0 {
+				dangling += rank[node]
+			}
+		}
+
+		next := make(map[string]float64, len(nodes))
+		for _, node := range nodes {
+			next[node] = (1-damping)/n + damping*dangling/n
+		}
+		for key, weight := range g.edges {
+			next[key.to] += damping * rank[key.from] * float64(weight) / outWeight[key.from]
+		}
+		rank = next
+	}
+
+	return rank
+}
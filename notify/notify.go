@@ -0,0 +1,186 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/pkg/errors"
+)
+
+// Notification tells about a change in a tracked candidate's profile
+type Notification struct {
+	candidate.Change
+	At time.Time
+}
+
+// String describes the notification in one line
+func (n Notification) String() string {
+	at := n.At.Format(time.RFC3339)
+	switch n.Kind {
+	case candidate.Added:
+		if n.After != "" {
+			return fmt.Sprintf("%s %s: new candidate in %s", at, n.Login, n.After)
+		}
+		return fmt.Sprintf("%s %s: new candidate", at, n.Login)
+	case candidate.Dropped:
+		return fmt.Sprintf("%s %s: no longer a candidate", at, n.Login)
+	}
+
+	return fmt.Sprintf("%s %s: %s changed from %q to %q", at, n.Login, n.Field, n.Before, n.After)
+}
+
+// Sink delivers notifications
+type Sink interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// Writer writes the notifications as lines of text, e.g. to stdout
+type Writer struct {
+	W io.Writer
+}
+
+// Notify implements Sink
+func (w Writer) Notify(ctx context.Context, n Notification) error {
+	_, err := fmt.Fprintln(w.W, n)
+	return err
+}
+
+// File appends the notifications to a file, as json lines
+type File struct {
+	Path string
+	mu   sync.Mutex
+}
+
+// Notify implements Sink
+func (f *File) Notify(ctx context.Context, n Notification) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.OpenFile(f.Path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	if err = json.NewEncoder(file).Encode(n); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// Erase removes the notifications about the login from the file, returning how many were removed
+func (f *File) Erase(login string) (int, error) {
+	return f.rewrite(func(n Notification) bool {
+		return !strings.EqualFold(n.Login, login) &&
+			!(n.Field == "login" && (strings.EqualFold(n.Before, login) || strings.EqualFold(n.After, login)))
+	})
+}
+
+// Prune removes the notifications older than the time from the file, returning how many were removed
+func (f *File) Prune(before time.Time) (int, error) {
+	return f.rewrite(func(n Notification) bool { return !n.At.Before(before) })
+}
+
+// rewrite keeps only the file's notifications that keep returns true for
+func (f *File) rewrite(keep func(n Notification) bool) (removed int, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	content, err := ioutil.ReadFile(f.Path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var kept bytes.Buffer
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	encoder := json.NewEncoder(&kept)
+	for decoder.More() {
+		var n Notification
+		if err = decoder.Decode(&n); err != nil {
+			return 0, errors.Wrapf(err, "invalid notification in %s", f.Path)
+		}
+		if !keep(n) {
+			removed++
+			continue
+		}
+		if err = encoder.Encode(n); err != nil {
+			return 0, err
+		}
+	}
+	if removed == 0 {
+		return 0, nil
+	}
+
+	return removed, ioutil.WriteFile(f.Path, kept.Bytes(), 0600)
+}
+
+// Webhook POSTs every notification as json to an URL
+type Webhook struct {
+	URL string
+	// Headers are added to the requests, e.g. for authentication
+	Headers map[string]string
+	// Client defaults to a client timing out after 10 seconds
+	Client *http.Client
+}
+
+// Notify implements Sink
+func (w Webhook) Notify(ctx context.Context, n Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range w.Headers {
+		req.Header.Set(key, value)
+	}
+
+	client := w.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "webhook request failed")
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode >= 300 {
+		return errors.Errorf("webhook answered %s", resp.Status)
+	}
+
+	return nil
+}
+
+// Sinks delivers the notifications to all of its sinks, returning the first error
+type Sinks []Sink
+
+// Notify implements Sink
+func (s Sinks) Notify(ctx context.Context, n Notification) (err error) {
+	for _, sink := range s {
+		if sinkErr := sink.Notify(ctx, n); sinkErr != nil && err == nil {
+			err = sinkErr
+		}
+	}
+
+	return
+}
@@ -0,0 +1,113 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// The supported output formats
+const (
+	FormatCsv   = "csv"
+	FormatJSON  = "json"
+	FormatTable = "table"
+)
+
+// Formats lists the supported output formats
+var Formats = []string{FormatCsv, FormatJSON, FormatTable}
+
+// Record is anything that can be written as a row
+type Record interface {
+	FormatForCsv() []string
+}
+
+// Writer writes records in one of the supported formats
+type Writer interface {
+	Write(r Record) error
+	// Flush writes any buffered data, table output only being aligned when flushed
+	Flush() error
+}
+
+// New returns a writer for the format. The header is used by the csv and table formats.
+func New(format string, w io.Writer, header []string) (Writer, error) {
+	switch format {
+	case FormatCsv:
+		return &csvWriter{w: csv.NewWriter(w), header: header}, nil
+	case FormatJSON:
+		return &jsonWriter{enc: json.NewEncoder(w)}, nil
+	case FormatTable:
+		return &tableWriter{w: tabwriter.NewWriter(w, 0, 4, 2, ' ', 0), header: header}, nil
+	}
+
+	return nil, fmt.Errorf("unknown format %q, use one of %s", format, strings.Join(Formats, ", "))
+}
+
+type csvWriter struct {
+	w             *csv.Writer
+	header        []string
+	headerWritten bool
+}
+
+func (c *csvWriter) Write(r Record) error {
+	if !c.headerWritten && c.header != nil {
+		if err := c.w.Write(c.header); err != nil {
+			return err
+		}
+		c.headerWritten = true
+	}
+
+	return c.w.Write(r.FormatForCsv())
+}
+
+func (c *csvWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// jsonWriter writes one json object per line
+type jsonWriter struct {
+	enc *json.Encoder
+}
+
+func (j *jsonWriter) Write(r Record) error {
+	return j.enc.Encode(r)
+}
+
+func (j *jsonWriter) Flush() error {
+	return nil
+}
+
+type tableWriter struct {
+	w             *tabwriter.Writer
+	header        []string
+	headerWritten bool
+}
+
+func (t *tableWriter) Write(r Record) error {
+	if !t.headerWritten && t.header != nil {
+		if err := t.row(t.header); err != nil {
+			return err
+		}
+		t.headerWritten = true
+	}
+
+	return t.row(r.FormatForCsv())
+}
+
+func (t *tableWriter) row(cells []string) error {
+	line := make([]string, len(cells))
+	for i, cell := range cells {
+		// keep multi line bios and such on their row
+		line[i] = strings.Join(strings.Fields(cell), " ")
+	}
+	_, err := fmt.Fprintln(t.w, strings.Join(line, "\t"))
+
+	return err
+}
+
+func (t *tableWriter) Flush() error {
+	return t.w.Flush()
+}
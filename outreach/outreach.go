@@ -0,0 +1,170 @@
+package outreach
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net/mail"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/pkg/errors"
+)
+
+// The draft formats
+const (
+	FormatEML      = "eml"
+	FormatMarkdown = "md"
+)
+
+// SubjectTemplate is the name of the template defining the subject, e.g. {{define "subject"}}Hi{{end}}
+const SubjectTemplate = "subject"
+
+// DefaultSubject is used when the template doesn't define a subject
+const DefaultSubject = `{{define "subject"}}Your work on {{.Repo}}{{end}}`
+
+// Data is what the templates get to personalize the drafts
+type Data struct {
+	Login     string
+	Name      string
+	FirstName string
+	Email     string
+	Location  string
+	Company   string
+	Profile   string
+	// Repo is the repo the candidate interacted the most with, Repos all of them
+	Repo  string
+	Repos []string
+	// Reviews, Comments and Commits are the candidate's interactions having links, by role
+	Reviews  []candidate.Interaction
+	Comments []candidate.Interaction
+	Commits  []candidate.Interaction
+	Roles    string
+}
+
+// NewData gathers the template data of a candidate
+func NewData(c *candidate.Candidate) Data {
+	d := Data{
+		Login:    c.Login(),
+		Name:     strings.TrimSpace(string(c.User.Name)),
+		Email:    string(c.User.Email),
+		Location: string(c.User.Location),
+		Company:  string(c.User.Company),
+		Profile:  "https://github.com/" + c.Login(),
+		Repos:    c.Repos(),
+		Roles:    c.Summary(),
+	}
+	d.FirstName = d.Login
+	if fields := strings.Fields(d.Name); len(fields) > 0 {
+		d.FirstName = fields[0]
+	}
+
+	counts := map[string]int{}
+	for _, i := range c.Interactions {
+		counts[i.Repo]++
+		if i.URL == "" {
+			continue
+		}
+		switch i.Role {
+		case candidate.Reviewer:
+			d.Reviews = append(d.Reviews, i)
+		case candidate.Commenter:
+			d.Comments = append(d.Comments, i)
+		case candidate.Committer:
+			d.Commits = append(d.Commits, i)
+		}
+	}
+	repos := append([]string{}, d.Repos...)
+	sort.SliceStable(repos, func(i, j int) bool { return counts[repos[i]] > counts[repos[j]] })
+	if len(repos) > 0 {
+		d.Repo = repos[0]
+	}
+
+	return d
+}
+
+// Draft is a rendered message, waiting for a human to review and send it
+type Draft struct {
+	Login   string
+	To      string
+	Subject string
+	Body    string
+}
+
+// Parse parses a draft template, adding the default subject if the template doesn't define one
+func Parse(name, text string) (*template.Template, error) {
+	funcs := template.FuncMap{"join": strings.Join}
+	tmpl, err := template.New(name).Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid template")
+	}
+	if tmpl.Lookup(SubjectTemplate) == nil {
+		if tmpl, err = tmpl.Parse(DefaultSubject); err != nil {
+			return nil, err
+		}
+	}
+
+	return tmpl, nil
+}
+
+// Render renders the candidate's draft
+func Render(tmpl *template.Template, c *candidate.Candidate) (Draft, error) {
+	data := NewData(c)
+
+	var body, subject bytes.Buffer
+	if err := tmpl.Execute(&body, data); err != nil {
+		return Draft{}, errors.Wrapf(err, "couldn't render %s's draft", data.Login)
+	}
+	if err := tmpl.ExecuteTemplate(&subject, SubjectTemplate, data); err != nil {
+		return Draft{}, errors.Wrapf(err, "couldn't render %s's subject", data.Login)
+	}
+
+	d := Draft{
+		Login:   data.Login,
+		Subject: strings.Join(strings.Fields(subject.String()), " "),
+		Body:    strings.TrimSpace(body.String()) + "\n",
+	}
+	if data.Email != "" {
+		d.To = (&mail.Address{Name: data.Name, Address: data.Email}).String()
+	}
+
+	return d, nil
+}
+
+// EML returns the draft as an unsent email message, which mail clients open for editing
+func (d Draft) EML(from string, date time.Time) []byte {
+	var b bytes.Buffer
+	if from != "" {
+		fmt.Fprintf(&b, "From: %s\r\n", from)
+	}
+	if d.To != "" {
+		fmt.Fprintf(&b, "To: %s\r\n", d.To)
+	}
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", d.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	b.WriteString("X-Unsent: 1\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(d.Body, "\n", "\r\n"))
+
+	return b.Bytes()
+}
+
+// Markdown returns the draft as a markdown document
+func (d Draft) Markdown() []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# %s\n\n", d.Subject)
+	if d.To != "" {
+		fmt.Fprintf(&b, "**To:** %s\n\n", d.To)
+	} else {
+		fmt.Fprintf(&b, "**To:** %s, no public email\n\n", d.Login)
+	}
+	b.WriteString("---\n\n")
+	b.WriteString(d.Body)
+
+	return b.Bytes()
+}
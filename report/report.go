@@ -0,0 +1,239 @@
+package report
+
+import (
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/state"
+	"github.com/pkg/errors"
+)
+
+// The formats a report can be rendered in
+const (
+	FormatHTML     = "html"
+	FormatMarkdown = "md"
+)
+
+// maxBars is how many bars a chart has, the remaining values being summed up as "other"
+const maxBars = 10
+
+//go:embed templates
+var templates embed.FS
+
+// Bar is one of a distribution's values together with how many candidates have it
+type Bar struct {
+	Label string
+	Count int
+}
+
+// Entry is a candidate together with their rank
+type Entry struct {
+	*candidate.Candidate
+	Rank  int
+	Score float64
+}
+
+// Anchor is the id of the candidate's detail section
+func (e Entry) Anchor() string {
+	return "candidate-" + strings.ToLower(e.Login())
+}
+
+// TopLanguages returns the candidate's top languages
+func (e Entry) TopLanguages() []string {
+	return e.Languages.Top(candidate.TopLanguagesCount)
+}
+
+// Report holds what a run's report shows
+type Report struct {
+	Run       *state.Run
+	Generated time.Time
+	// Candidates are ranked by score
+	Candidates []Entry
+	// Roles counts the candidates having each role
+	Roles     []Bar
+	Locations []Bar
+	Languages []Bar
+}
+
+// New prepares the run's report over the given candidates
+func New(run *state.Run, candidates []*candidate.Candidate, now time.Time) *Report {
+	r := &Report{Run: run, Generated: now}
+
+	for _, c := range candidates {
+		r.Candidates = append(r.Candidates, Entry{Candidate: c, Score: c.Score(now)})
+	}
+	sort.SliceStable(r.Candidates, func(i, j int) bool { return r.Candidates[i].Score > r.Candidates[j].Score })
+
+	var locations, languages []string
+	roles := map[candidate.Role]int{}
+	for i := range r.Candidates {
+		e := &r.Candidates[i]
+		e.Rank = i + 1
+		for _, role := range e.Roles("") {
+			roles[role]++
+		}
+		locations = append(locations, strings.TrimSpace(string(e.User.Location)))
+		languages = append(languages, e.Languages.Top(candidate.TopLanguagesCount)...)
+	}
+	for _, role := range candidate.Roles {
+		r.Roles = append(r.Roles, Bar{Label: string(role), Count: roles[role]})
+	}
+	r.Locations = Distribution(locations, maxBars)
+	r.Languages = Distribution(languages, maxBars)
+
+	return r
+}
+
+// Duration returns how long the run took
+func (r *Report) Duration() time.Duration {
+	if r.Run.Finished.IsZero() {
+		return 0
+	}
+
+	return r.Run.Finished.Sub(r.Run.Started).Round(time.Second)
+}
+
+// Distribution counts the values case insensitively, the most frequent first. Empty values count as
+// "unknown" and the ones beyond the max most frequent are summed up as "other".
+func Distribution(values []string, max int) (bars []Bar) {
+	index := map[string]int{}
+	for _, value := range values {
+		if value == "" {
+			value = "unknown"
+		}
+		key := strings.ToLower(value)
+		if i, ok := index[key]; ok {
+			bars[i].Count++
+			continue
+		}
+		index[key] = len(bars)
+		bars = append(bars, Bar{Label: value, Count: 1})
+	}
+	sort.SliceStable(bars, func(i, j int) bool { return bars[i].Count > bars[j].Count })
+
+	if len(bars) > max {
+		other := Bar{Label: "other"}
+		for _, bar := range bars[max-1:] {
+			other.Count += bar.Count
+		}
+		bars = append(bars[:max-1], other)
+	}
+
+	return
+}
+
+// Write renders the report in the format
+func (r *Report) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatHTML:
+		tmpl, err := htmltemplate.New("report.html").Funcs(htmltemplate.FuncMap{
+			"chart":   chart,
+			"score":   formatScore,
+			"profile": profileURL,
+			"join":    strings.Join,
+		}).ParseFS(templates, "templates/report.html")
+		if err != nil {
+			return errors.Wrap(err, "couldn't parse the html template")
+		}
+		return tmpl.Execute(w, r)
+	case FormatMarkdown:
+		tmpl, err := template.New("report.md").Funcs(template.FuncMap{
+			"cell":    markdownCell,
+			"bar":     textBar,
+			"score":   formatScore,
+			"profile": profileURL,
+			"join":    strings.Join,
+		}).ParseFS(templates, "templates/report.md")
+		if err != nil {
+			return errors.Wrap(err, "couldn't parse the markdown template")
+		}
+		return tmpl.Execute(w, r)
+	}
+
+	return errors.Errorf("unknown report format %q, expecting %s or %s", format, FormatHTML, FormatMarkdown)
+}
+
+func formatScore(score float64) string {
+	return strconv.FormatFloat(score, 'f', 2, 64)
+}
+
+func profileURL(login string) string {
+	return "https://github.com/" + login
+}
+
+// markdownCell keeps the value from breaking a markdown table
+func markdownCell(value string) string {
+	return strings.NewReplacer("|", `\|`, "\r", " ", "\n", " ").Replace(value)
+}
+
+// textBar draws the bar with blocks, the widest of the bars being width blocks long
+func textBar(bars []Bar, bar Bar, width int) string {
+	max := 0
+	for _, b := range bars {
+		if b.Count > max {
+			max = b.Count
+		}
+	}
+	n := 0
+	if max > 0 {
+		n = (bar.Count*width + max - 1) / max
+	}
+
+	return strings.Repeat("█", n)
+}
+
+// The geometry of the charts, in pixels
+const (
+	chartWidth  = 640
+	labelWidth  = 180
+	barHeight   = 20
+	barGap      = 6
+	labelLength = 26
+)
+
+// chart draws the bars as an inline svg horizontal bar chart
+func chart(title string, bars []Bar) htmltemplate.HTML {
+	if len(bars) == 0 {
+		return htmltemplate.HTML(`<p class="empty">no data</p>`)
+	}
+
+	max := 0
+	for _, bar := range bars {
+		if bar.Count > max {
+			max = bar.Count
+		}
+	}
+	height := len(bars) * (barHeight + barGap)
+	room := chartWidth - labelWidth - 50
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" role="img" aria-label="%s">`,
+		chartWidth, height, chartWidth, height, htmltemplate.HTMLEscapeString(title))
+	for i, bar := range bars {
+		y := i * (barHeight + barGap)
+		width := 0
+		if max > 0 {
+			width = bar.Count * room / max
+		}
+		label := bar.Label
+		if runes := []rune(label); len(runes) > labelLength {
+			label = string(runes[:labelLength-1]) + "…"
+		}
+		fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="end">%s</text>`, labelWidth-8, y+barHeight-5,
+			htmltemplate.HTMLEscapeString(label))
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" rx="2"><title>%s: %d</title></rect>`,
+			labelWidth, y, width, barHeight, htmltemplate.HTMLEscapeString(bar.Label), bar.Count)
+		fmt.Fprintf(&b, `<text x="%d" y="%d">%d</text>`, labelWidth+width+6, y+barHeight-5, bar.Count)
+	}
+	b.WriteString(`</svg>`)
+
+	return htmltemplate.HTML(b.String())
+}
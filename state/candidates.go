@@ -0,0 +1,21 @@
+package state
+
+import "github.com/florinutz/gh-recruiter/candidate"
+
+// CandidatesKey is the key the candidate pipeline is stored under
+const CandidatesKey = "candidates"
+
+// LoadCandidates returns the stored candidate pipeline, empty if there's none
+func (s *Store) LoadCandidates() (*candidate.DB, error) {
+	db := &candidate.DB{}
+	if _, err := s.Load(CandidatesKey, db); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// SaveCandidates persists the candidate pipeline
+func (s *Store) SaveCandidates(db *candidate.DB) error {
+	return s.Save(CandidatesKey, db)
+}
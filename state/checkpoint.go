@@ -0,0 +1,114 @@
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+)
+
+// Checkpoint records how far a crawl of one of a repo's connections (forks, PRs...) got
+type Checkpoint struct {
+	Owner      string
+	Name       string
+	Connection string
+	// EndCursor is the cursor of the last page that was fetched
+	EndCursor string
+	// Listed is set once the connection was walked entirely
+	Listed bool
+	// Truncated is set when the walk stopped at the page limit, before the connection's end or the since mark
+	Truncated bool
+	// Logins holds the logins collected so far, grouped by the role they had (forker, reviewer...)
+	Logins map[string][]string
+	// Resolved holds the logins whose profiles were already fetched and handled, by role, as the same
+	// login is handled once for each role it had
+	Resolved map[string]map[string]bool
+	// Interactions holds the interactions that have urls, so that a resumed crawl still links to them
+	Interactions []Interaction `json:",omitempty"`
+	// Newest is the timestamp of the newest item fetched, used to advance the repo's marks
+	Newest    time.Time
+	UpdatedAt time.Time
+}
+
+// Interaction is a user's interaction with a PR, like a comment or a review
+type Interaction struct {
+	Login string
+	Role  candidate.Role
+	URL   string
+	// Author is the login of the PR's author
+	Author string
+}
+
+// NewCheckpoint returns an empty checkpoint for the connection
+func NewCheckpoint(owner, name, connection string) *Checkpoint {
+	return &Checkpoint{
+		Owner:      owner,
+		Name:       name,
+		Connection: connection,
+		Logins:     map[string][]string{},
+		Resolved:   map[string]map[string]bool{},
+	}
+}
+
+// Key is the key the checkpoint is stored under
+func (c *Checkpoint) Key() string {
+	return CheckpointKey(c.Owner, c.Name, c.Connection)
+}
+
+// CheckpointKey computes the key a repo connection's checkpoint is stored under
+func CheckpointKey(owner, name, connection string) string {
+	return fmt.Sprintf("checkpoint-%s-%s-%s", owner, name, connection)
+}
+
+// Resolve records that the login was handled in the role
+func (c *Checkpoint) Resolve(role, login string) {
+	if c.Resolved[role] == nil {
+		c.Resolved[role] = map[string]bool{}
+	}
+	c.Resolved[role][login] = true
+}
+
+// ResolvedCount returns how many logins were resolved, in all roles
+func (c *Checkpoint) ResolvedCount() (n int) {
+	for _, logins := range c.Resolved {
+		n += len(logins)
+	}
+
+	return
+}
+
+// Unresolved returns the distinct logins that weren't resolved yet in the role, the empty ones (of deleted
+// accounts) left aside
+func (c *Checkpoint) Unresolved(role string, logins []string) (result []string) {
+	seen := map[string]bool{}
+	for _, login := range logins {
+		if login != "" && !c.Resolved[role][login] && !seen[login] {
+			seen[login] = true
+			result = append(result, login)
+		}
+	}
+
+	return
+}
+
+// LoadCheckpoint returns the stored checkpoint for the repo connection, or a fresh one if there's none
+func (s *Store) LoadCheckpoint(owner, name, connection string) (*Checkpoint, error) {
+	c := NewCheckpoint(owner, name, connection)
+	if _, err := s.Load(c.Key(), c); err != nil {
+		return NewCheckpoint(owner, name, connection), err
+	}
+	if c.Logins == nil {
+		c.Logins = map[string][]string{}
+	}
+	if c.Resolved == nil {
+		c.Resolved = map[string]map[string]bool{}
+	}
+
+	return c, nil
+}
+
+// SaveCheckpoint persists the checkpoint
+func (s *Store) SaveCheckpoint(c *Checkpoint) error {
+	c.UpdatedAt = time.Now()
+	return s.Save(c.Key(), c)
+}
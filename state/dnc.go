@@ -0,0 +1,101 @@
+package state
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// DoNotContactKey is the key the do-not-contact list is stored under
+const DoNotContactKey = "do-not-contact"
+
+// DoNotContactCsvHeader is the header of the do-not-contact entries' csv rows
+var DoNotContactCsvHeader = []string{"Login", "ID", "Reason", "Added"}
+
+// DoNotContactEntry is a person that asked not to be contacted
+type DoNotContactEntry struct {
+	// Login is the login the person had when added, ID is their stable github node id
+	Login  string
+	ID     string
+	Reason string
+	Added  time.Time
+}
+
+// FormatForCsv returns the entry as a csv row
+func (e DoNotContactEntry) FormatForCsv() []string {
+	return []string{e.Login, e.ID, e.Reason, e.Added.Format("02-Jan-2006")}
+}
+
+// DoNotContact is the list of the people that must never be reported, safe for concurrent use
+type DoNotContact struct {
+	Entries []DoNotContactEntry
+	mu      sync.RWMutex
+}
+
+// Lookup finds the entry matching the id, which survives login renames, or else the login
+func (d *DoNotContact) Lookup(login, id string) (DoNotContactEntry, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	for _, e := range d.Entries {
+		if id != "" && e.ID == id {
+			return e, true
+		}
+	}
+	for _, e := range d.Entries {
+		if login != "" && strings.EqualFold(e.Login, login) {
+			return e, true
+		}
+	}
+
+	return DoNotContactEntry{}, false
+}
+
+// Add adds the entry, replacing the one having the same login or id
+func (d *DoNotContact) Add(entry DoNotContactEntry) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for i, e := range d.Entries {
+		if strings.EqualFold(e.Login, entry.Login) || (entry.ID != "" && e.ID == entry.ID) {
+			d.Entries[i] = entry
+			return
+		}
+	}
+	d.Entries = append(d.Entries, entry)
+}
+
+// Remove removes the entries matching the login or id, telling whether there were any
+func (d *DoNotContact) Remove(loginOrID string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	kept := d.Entries[:0]
+	for _, e := range d.Entries {
+		if !strings.EqualFold(e.Login, loginOrID) && e.ID != loginOrID {
+			kept = append(kept, e)
+		}
+	}
+	removed := len(kept) != len(d.Entries)
+	d.Entries = kept
+
+	return removed
+}
+
+// LoadDoNotContact returns the stored do-not-contact list, empty if there's none
+func (s *Store) LoadDoNotContact() (*DoNotContact, error) {
+	d := &DoNotContact{}
+	if _, err := s.Load(DoNotContactKey, d); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// SaveDoNotContact persists the do-not-contact list
+func (s *Store) SaveDoNotContact(d *DoNotContact) error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return s.Save(DoNotContactKey, d)
+}
@@ -0,0 +1,178 @@
+package state
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErasuresKey is the key the audit log of erasures is stored under
+const ErasuresKey = "erasures"
+
+// ErasureCsvHeader is the header of the erasures' csv rows
+var ErasureCsvHeader = []string{"Subject", "Reason", "At", "Cache entries", "State files", "Files"}
+
+// Erasure records what personal data was erased, when and why
+type Erasure struct {
+	// Subject is the erased login, empty for retention policy prunes
+	Subject      string
+	Reason       string
+	At           time.Time
+	CacheEntries int
+	StateKeys    []string
+	// Files are the other files the subject was removed from, like the exclusions audit
+	Files []string `json:",omitempty"`
+}
+
+// FormatForCsv returns the erasure as a csv row
+func (e Erasure) FormatForCsv() []string {
+	return []string{e.Subject, e.Reason, e.At.Format(time.RFC3339), strconv.Itoa(e.CacheEntries),
+		strings.Join(e.StateKeys, " "), strings.Join(e.Files, " ")}
+}
+
+// Keys returns the keys of the stored items starting with prefix
+func (s *Store) Keys(prefix string) ([]string, error) {
+	files, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, f := range files {
+		key := strings.TrimSuffix(f.Name(), filepath.Ext(f.Name()))
+		if filepath.Ext(f.Name()) == ".json" && strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys, nil
+}
+
+// Erase removes the login from the crawl state, the candidate pipeline and the recorded runs,
+// returning the keys of the items it was removed from
+func (s *Store) Erase(login string) (erased []string, err error) {
+	keys, err := s.Keys("marks-")
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		m := &Marks{}
+		if _, err = s.Load(key, m); err != nil {
+			return erased, err
+		}
+		if !eraseSeen(m.Seen, login) {
+			continue
+		}
+		if err = s.Save(key, m); err != nil {
+			return erased, err
+		}
+		erased = append(erased, key)
+	}
+
+	db, err := s.LoadCandidates()
+	if err != nil {
+		return erased, err
+	}
+	if db.Remove(login) {
+		if err = s.SaveCandidates(db); err != nil {
+			return erased, err
+		}
+		erased = append(erased, CandidatesKey)
+	}
+
+	if keys, err = s.Keys(RunKey("")); err != nil {
+		return erased, err
+	}
+	for _, key := range keys {
+		r := &Run{}
+		if _, err = s.Load(key, r); err != nil {
+			return erased, err
+		}
+		kept := r.Candidates[:0]
+		for _, c := range r.Candidates {
+			if !strings.EqualFold(c.Login(), login) {
+				kept = append(kept, c)
+			}
+		}
+		if len(kept) == len(r.Candidates) {
+			continue
+		}
+		r.Candidates = kept
+		if err = s.Save(key, r); err != nil {
+			return erased, err
+		}
+		erased = append(erased, key)
+	}
+
+	if keys, err = s.Keys("checkpoint-"); err != nil {
+		return erased, err
+	}
+	for _, key := range keys {
+		c := &Checkpoint{}
+		if _, err = s.Load(key, c); err != nil {
+			return erased, err
+		}
+		found := false
+		for _, resolved := range c.Resolved {
+			found = eraseSeen(resolved, login) || found
+		}
+		interactions := c.Interactions[:0]
+		for _, i := range c.Interactions {
+			if !strings.EqualFold(i.Login, login) && !strings.EqualFold(i.Author, login) {
+				interactions = append(interactions, i)
+			}
+		}
+		found = found || len(interactions) != len(c.Interactions)
+		c.Interactions = interactions
+		for role, logins := range c.Logins {
+			kept := logins[:0]
+			for _, l := range logins {
+				if !strings.EqualFold(l, login) {
+					kept = append(kept, l)
+				}
+			}
+			found = found || len(kept) != len(logins)
+			c.Logins[role] = kept
+		}
+		if !found {
+			continue
+		}
+		if err = s.Save(key, c); err != nil {
+			return erased, err
+		}
+		erased = append(erased, key)
+	}
+
+	return erased, nil
+}
+
+func eraseSeen(seen map[string]bool, login string) (found bool) {
+	for l := range seen {
+		if strings.EqualFold(l, login) {
+			delete(seen, l)
+			found = true
+		}
+	}
+
+	return
+}
+
+// LoadErasures returns the audit log of erasures, oldest first
+func (s *Store) LoadErasures() ([]Erasure, error) {
+	var erasures []Erasure
+	_, err := s.Load(ErasuresKey, &erasures)
+
+	return erasures, err
+}
+
+// LogErasure appends the erasure to the audit log
+func (s *Store) LogErasure(e Erasure) error {
+	erasures, err := s.LoadErasures()
+	if err != nil {
+		return err
+	}
+
+	return s.Save(ErasuresKey, append(erasures, e))
+}
@@ -0,0 +1,50 @@
+package state
+
+import (
+	"fmt"
+	"time"
+)
+
+// Marks are a repo's high-water marks: the newest items seen by previous runs,
+// so that the next run only needs to fetch what came after them
+type Marks struct {
+	Owner         string
+	Name          string
+	PRUpdatedAt   time.Time
+	ForkCreatedAt time.Time
+	StarStarredAt time.Time
+	// Seen holds the logins of the candidates reported by previous runs
+	Seen    map[string]bool
+	LastRun time.Time
+}
+
+// MarksKey computes the key a repo's marks are stored under
+func MarksKey(owner, name string) string {
+	return fmt.Sprintf("marks-%s-%s", owner, name)
+}
+
+// LoadMarks returns the stored marks for the repo, or empty ones if it was never crawled
+func (s *Store) LoadMarks(owner, name string) (*Marks, error) {
+	m := &Marks{Owner: owner, Name: name}
+	if _, err := s.Load(MarksKey(owner, name), m); err != nil {
+		return &Marks{Owner: owner, Name: name, Seen: map[string]bool{}}, err
+	}
+	if m.Seen == nil {
+		m.Seen = map[string]bool{}
+	}
+
+	return m, nil
+}
+
+// SaveMarks persists the repo's marks
+func (s *Store) SaveMarks(m *Marks) error {
+	m.LastRun = time.Now()
+	return s.Save(MarksKey(m.Owner, m.Name), m)
+}
+
+// Advance moves the mark forward to t, if t is newer
+func Advance(mark *time.Time, t time.Time) {
+	if t.After(*mark) {
+		*mark = t
+	}
+}
@@ -0,0 +1,148 @@
+package state
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/fetch"
+	"github.com/pkg/errors"
+)
+
+// runIDFormat formats the run start times into run ids
+const runIDFormat = "20060102-150405"
+
+// RunCsvHeader is the header matching Run.FormatForCsv
+var RunCsvHeader = []string{"ID", "Command", "Repos", "Started", "Duration", "Candidates"}
+
+// Run is a recorded crawl
+type Run struct {
+	ID      string
+	Command string
+	Args    []string
+	// Config is a snapshot of the settings the crawl ran with, tokens excluded
+	Config     map[string]interface{}
+	Repos      []string
+	Started    time.Time
+	Finished   time.Time
+	Candidates []*candidate.Candidate
+	// Stats counts the crawl's api queries and their cost
+	Stats fetch.Stats
+}
+
+// NewRun starts recording a crawl
+func NewRun(command string, args []string, config map[string]interface{}, started time.Time) *Run {
+	return &Run{
+		ID:      started.Format(runIDFormat),
+		Command: command,
+		Args:    args,
+		Config:  config,
+		Started: started,
+	}
+}
+
+// AddRepo records that the crawl analyzed the repo
+func (r *Run) AddRepo(nameWithOwner string) {
+	for _, repo := range r.Repos {
+		if repo == nameWithOwner {
+			return
+		}
+	}
+	r.Repos = append(r.Repos, nameWithOwner)
+}
+
+// FormatForCsv returns the run as a csv row
+func (r *Run) FormatForCsv() []string {
+	duration := ""
+	if !r.Finished.IsZero() {
+		duration = r.Finished.Sub(r.Started).Round(time.Second).String()
+	}
+
+	return []string{r.ID, strings.TrimSpace(r.Command + " " + strings.Join(r.Args, " ")),
+		strings.Join(r.Repos, " "), r.Started.Format(time.RFC3339), duration, strconv.Itoa(len(r.Candidates))}
+}
+
+// RunKey computes the key a run is stored under
+func RunKey(id string) string {
+	return "run-" + id
+}
+
+// SaveRun persists the run. When another run started in the same second already took its id,
+// the run's id gets a numeric suffix.
+func (s *Store) SaveRun(r *Run) error {
+	id := r.ID
+	for n := 2; ; n++ {
+		existing := &Run{}
+		found, err := s.Load(RunKey(r.ID), existing)
+		if err != nil {
+			return err
+		}
+		if !found || existing.Started.Equal(r.Started) {
+			break
+		}
+		r.ID = fmt.Sprintf("%s-%d", id, n)
+	}
+
+	return s.Save(RunKey(r.ID), r)
+}
+
+// PruneRuns deletes the runs finished before the given time, returning their keys
+func (s *Store) PruneRuns(before time.Time) (pruned []string, err error) {
+	ids, err := s.RunIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		r := &Run{}
+		if _, err = s.Load(RunKey(id), r); err != nil {
+			return pruned, err
+		}
+		finished := r.Finished
+		if finished.IsZero() {
+			finished = r.Started
+		}
+		if !finished.Before(before) {
+			continue
+		}
+		if err = s.Delete(RunKey(id)); err != nil {
+			return pruned, err
+		}
+		pruned = append(pruned, RunKey(id))
+	}
+
+	return pruned, nil
+}
+
+// LoadRun returns the run having the id
+func (s *Store) LoadRun(id string) (*Run, error) {
+	r := &Run{}
+	found, err := s.Load(RunKey(id), r)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, errors.Errorf("no run %s", id)
+	}
+
+	return r, nil
+}
+
+// RunIDs returns the ids of the recorded runs, oldest first
+func (s *Store) RunIDs() ([]string, error) {
+	keys, err := s.Keys(RunKey(""))
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(keys))
+	for i, key := range keys {
+		ids[i] = strings.TrimPrefix(key, RunKey(""))
+	}
+	sort.Strings(ids)
+
+	return ids, nil
+}
@@ -0,0 +1,96 @@
+package state
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/pkg/errors"
+)
+
+// Store persists crawl state as json files inside a directory
+type Store struct {
+	dir string
+}
+
+// NewStore returns a store living in the user's cache dir, under dirName
+func NewStore(dirName string) (*Store, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewStoreAt(filepath.Join(cacheDir, dirName))
+}
+
+// NewStoreAt returns a store living in dir, creating it if needed
+func NewStoreAt(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrap(err, "couldn't create state dir")
+	}
+
+	return &Store{dir: dir}, nil
+}
+
+// Dir returns the directory the store writes to
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+var unsafeKeyChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+func (s *Store) path(key string) string {
+	return filepath.Join(s.dir, unsafeKeyChars.ReplaceAllString(key, "_")+".json")
+}
+
+// Load decodes the item stored under key into v. It returns false if there's no such item.
+func (s *Store) Load(key string, v interface{}) (bool, error) {
+	data, err := ioutil.ReadFile(s.path(key))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err = json.Unmarshal(data, v); err != nil {
+		return false, errors.Wrapf(err, "corrupt state for key %s", key)
+	}
+
+	return true, nil
+}
+
+// Save stores v under key, replacing the previous item atomically
+func (s *Store) Save(key string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "state encoding error")
+	}
+
+	tmp, err := ioutil.TempFile(s.dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path(key))
+}
+
+// Delete removes the item stored under key, if any
+func (s *Store) Delete(key string) error {
+	if err := os.Remove(s.path(key)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/fetch"
+	"github.com/florinutz/gh-recruiter/filter"
+	"github.com/shurcooL/githubv4"
+)
+
+var activityNow = time.Date(2018, 11, 20, 0, 0, 0, 0, time.UTC)
+
+// activity returns the given contributions, last pushing the given number of days ago
+func activity(commits, reviews int, daysAgo int) *fetch.Activity {
+	a := &fetch.Activity{}
+	a.Contributions.TotalCommitContributions = githubv4.Int(commits)
+	a.Contributions.TotalPullRequestReviewContributions = githubv4.Int(reviews)
+	if daysAgo >= 0 {
+		a.RecentRepos.Nodes = append(a.RecentRepos.Nodes, struct{ PushedAt githubv4.DateTime }{
+			githubv4.DateTime{Time: activityNow.AddDate(0, 0, -daysAgo)},
+		})
+	}
+
+	return a
+}
+
+func TestActivityFilter(t *testing.T) {
+	fetched := activity(8, 4, 30)
+	f := filter.Activity{
+		Fetch: func(ctx context.Context, login string) (fetch.Activity, error) {
+			if login == "unknown" {
+				return fetch.Activity{}, errors.New("not found")
+			}
+			return *fetched, nil
+		},
+		Within:           180 * 24 * time.Hour,
+		MinContributions: 10,
+		Now:              func() time.Time { return activityNow },
+	}
+
+	tests := []struct {
+		name     string
+		login    string
+		activity *fetch.Activity
+		want     bool
+	}{
+		{"active", "someone", activity(8, 4, 30), true},
+		{"too few contributions", "someone", activity(5, 0, 30), false},
+		{"dormant", "someone", activity(100, 0, 400), false},
+		{"never active", "someone", activity(100, 0, -1), false},
+		{"fetched", "someone", nil, true},
+		{"fetch error", "unknown", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &candidate.Candidate{User: fetch.User{Login: githubv4.String(tt.login)}, Activity: tt.activity}
+			if ok, reason := f.Match(context.Background(), c); ok != tt.want {
+				t.Errorf("Activity.Match() = %v (%s), want %v", ok, reason, tt.want)
+			}
+			if tt.activity == nil && tt.want && c.Activity == nil {
+				t.Error("Activity.Match() should keep the fetched activity on the candidate")
+			}
+		})
+	}
+}
+
+func TestCandidate_Score(t *testing.T) {
+	committer := &candidate.Candidate{
+		Activity:     activity(200, 50, 10),
+		Interactions: []candidate.Interaction{{Repo: "a/a", Role: candidate.Committer}},
+	}
+	stargazer := &candidate.Candidate{
+		Activity:     activity(200, 50, 10),
+		Interactions: []candidate.Interaction{{Repo: "a/a", Role: candidate.Stargazer}},
+	}
+	dormant := &candidate.Candidate{
+		Activity:     activity(200, 50, 3*365),
+		Interactions: []candidate.Interaction{{Repo: "a/a", Role: candidate.Committer}},
+	}
+
+	if committer.Score(activityNow) <= stargazer.Score(activityNow) {
+		t.Error("committers should score better than stargazers")
+	}
+	if dormant.Score(activityNow) >= committer.Score(activityNow)/3 {
+		t.Errorf("dormant candidates should decay: %f vs %f", dormant.Score(activityNow), committer.Score(activityNow))
+	}
+}
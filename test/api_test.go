@@ -0,0 +1,159 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/api"
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/fetch"
+	"github.com/florinutz/gh-recruiter/web"
+	"github.com/shurcooL/githubv4"
+)
+
+// fakeCrawl finds a reviewer in every repo but the failing/repo one
+func fakeCrawl(ctx context.Context, req api.CrawlRequest, found func(c *candidate.Candidate)) (*candidate.Set, error) {
+	if req.Repo == "failing/repo" {
+		return nil, errors.New("boom")
+	}
+
+	set := candidate.NewSet()
+	c := set.Add(fetch.User{ID: "MDQ6VXNlcjE=", Login: "reviewer", Location: githubv4.String("Berlin")},
+		candidate.Interaction{Repo: req.Repo, Role: candidate.Reviewer})
+	found(c)
+
+	return set, nil
+}
+
+func TestServer(t *testing.T) {
+	saved := 0
+	db := &candidate.DB{}
+	db.Track(&candidate.Candidate{User: fetch.User{Login: "blocked"}}, time.Now())
+	server := api.NewServer("s3cr3t", db, func(*candidate.DB) error { saved++; return nil }, fakeCrawl)
+	server.Contactable = func(c *candidate.Candidate) bool { return c.Login() != "blocked" }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go server.Run(ctx)
+
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	call := func(method, path string, body interface{}, into interface{}) int {
+		var payload bytes.Buffer
+		if body != nil {
+			json.NewEncoder(&payload).Encode(body)
+		}
+		req, err := http.NewRequest(method, ts.URL+path, &payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", "Bearer s3cr3t")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if into != nil {
+			json.NewDecoder(resp.Body).Decode(into)
+		}
+		return resp.StatusCode
+	}
+
+	if resp, err := http.Get(ts.URL + "/api/candidates"); err != nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("unauthenticated request got %v, %v", resp.Status, err)
+	}
+
+	var crawl api.Crawl
+	if status := call("POST", "/api/crawls", api.CrawlRequest{Repo: "hashicorp/hcl", PRs: true}, &crawl); status != http.StatusAccepted {
+		t.Fatalf("POST /api/crawls = %d", status)
+	}
+	if status := call("POST", "/api/crawls", api.CrawlRequest{Repo: "no-slash"}, nil); status != http.StatusBadRequest {
+		t.Errorf("POST /api/crawls with an invalid repo = %d", status)
+	}
+	call("POST", "/api/crawls", api.CrawlRequest{Repo: "failing/repo"}, nil)
+
+	// wait for the second crawl, crawls running in order
+	deadline := time.Now().Add(5 * time.Second)
+	var failed api.Crawl
+	for failed.State != api.CrawlFailed && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		call("GET", "/api/crawls/2", nil, &failed)
+	}
+	if failed.Error != "boom" {
+		t.Fatalf("GET /api/crawls/2 = %+v", failed)
+	}
+	call("GET", "/api/crawls/1", nil, &crawl)
+	if crawl.State != api.CrawlDone || crawl.Candidates != 1 {
+		t.Errorf("GET /api/crawls/1 = %+v", crawl)
+	}
+
+	var listed []api.Candidate
+	if call("GET", "/api/candidates?q=berlin&role=reviewer", nil, &listed); len(listed) != 1 || listed[0].Login != "reviewer" {
+		t.Errorf("GET /api/candidates = %+v", listed)
+	}
+	if call("GET", "/api/candidates?status=contacted", nil, &listed); len(listed) != 0 {
+		t.Errorf("GET /api/candidates?status=contacted = %+v", listed)
+	}
+
+	var updated api.Candidate
+	if status := call("POST", "/api/candidates/reviewer/status", map[string]string{"status": "contacted"}, &updated); status != http.StatusOK || updated.Status != candidate.Contacted {
+		t.Errorf("POST /api/candidates/reviewer/status = %d, %+v", status, updated)
+	}
+	if status := call("POST", "/api/candidates/reviewer/status", map[string]string{"status": "ghosted"}, nil); status != http.StatusBadRequest {
+		t.Errorf("POST with an unknown status = %d", status)
+	}
+	if status := call("GET", "/api/candidates/blocked", nil, nil); status != http.StatusNotFound {
+		t.Errorf("GET of a do-not-contact candidate = %d", status)
+	}
+	if saved != 2 {
+		t.Errorf("the pipeline was saved %d times, want 2", saved)
+	}
+}
+
+func TestServer_UI_DoNotContact(t *testing.T) {
+	db := &candidate.DB{}
+	db.Track(&candidate.Candidate{User: fetch.User{Login: "someone"}}, time.Now())
+	server := api.NewServer("s3cr3t", db, func(*candidate.DB) error { return nil }, fakeCrawl)
+	blocked := map[string]string{}
+	server.Contactable = func(c *candidate.Candidate) bool { _, found := blocked[c.Login()]; return !found }
+	server.DoNotContact = func(c *candidate.Candidate, reason string) error {
+		blocked[c.Login()] = reason
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/api/", server.Handler())
+	mux.Handle("/", web.Handler())
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	for _, asset := range []string{"/", "/app.js", "/style.css"} {
+		resp, err := http.Get(ts.URL + asset)
+		if err != nil || resp.StatusCode != http.StatusOK {
+			t.Errorf("GET %s = %v, %v", asset, resp, err)
+			continue
+		}
+		resp.Body.Close()
+	}
+
+	req, _ := http.NewRequest("POST", ts.URL+"/api/candidates/someone/dnc", strings.NewReader(`{"reason": "asked"}`))
+	req.Header.Set("X-API-Key", "s3cr3t")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil || resp.StatusCode != http.StatusNoContent || blocked["someone"] != "asked" {
+		t.Fatalf("POST /api/candidates/someone/dnc = %v, %v, blocked %v", resp, err, blocked)
+	}
+
+	req, _ = http.NewRequest("GET", ts.URL+"/api/candidates/someone", nil)
+	req.Header.Set("X-API-Key", "s3cr3t")
+	if resp, err = http.DefaultClient.Do(req); err != nil || resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET of a candidate put on the do-not-contact list = %v, %v", resp, err)
+	}
+}
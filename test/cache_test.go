@@ -1,16 +1,54 @@
 package test
 
 import (
-	"github.com/florinutz/gh-recruiter/cache"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
 	"reflect"
 	"testing"
 	"time"
+
+	"github.com/florinutz/gh-recruiter/cache"
+	"github.com/florinutz/gh-recruiter/fetch"
+	"github.com/shurcooL/githubv4"
 )
 
 type ForCaching struct {
 	Caca string
 }
 
+type withScalars struct {
+	Nodes []struct {
+		URL       githubv4.URI
+		UpdatedAt githubv4.DateTime
+		Count     *githubv4.Int
+	}
+}
+
+func scalars(rawurl string) withScalars {
+	var q withScalars
+	u, _ := url.Parse(rawurl)
+	count := githubv4.Int(3)
+	q.Nodes = append(q.Nodes, struct {
+		URL       githubv4.URI
+		UpdatedAt githubv4.DateTime
+		Count     *githubv4.Int
+	}{githubv4.URI{URL: u}, githubv4.DateTime{Time: time.Date(2018, 11, 20, 10, 0, 0, 0, time.UTC)}, &count})
+
+	return q
+}
+
+// unsetURI returns a query whose second node's url is unset
+func unsetURI() withScalars {
+	q := scalars("https://github.com")
+	q.Nodes = append(q.Nodes, q.Nodes[0])
+	q.Nodes[1].URL = githubv4.URI{}
+
+	return q
+}
+
 func TestCache_Write_Read_Query(t *testing.T) {
 	type args struct {
 		q         interface{}
@@ -43,17 +81,45 @@ func TestCache_Write_Read_Query(t *testing.T) {
 			false,
 			false,
 		},
+		{
+			"githubv4 scalars",
+			"scalars",
+			5 * time.Second,
+			args{q: scalars("https://github.com/hashicorp/hcl/pull/1")},
+			scalars("https://github.com/hashicorp/hcl/pull/1"),
+			false,
+			false,
+		},
+		{
+			"unset uri",
+			"unset",
+			5 * time.Second,
+			args{q: unsetURI()},
+			nil,
+			false,
+			true,
+		},
 	}
 
+	defer os.Setenv("XDG_CACHE_HOME", os.Getenv("XDG_CACHE_HOME"))
+	os.Setenv("XDG_CACHE_HOME", t.TempDir())
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c, err := cache.NewCache(tt.bucket, tt.validity)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer c.Close()
 
 			err = c.WriteQuery(tt.args.q, tt.args.variables)
 			if (err != nil) != tt.wantWriteErr {
 				t.Errorf("Cache.WriteQuery()\nerror: %v\nwantWriteErr %v", err, tt.wantWriteErr)
 				return
 			}
+			if tt.wantWriteErr {
+				return
+			}
 
 			got, err := c.ReadQuery(tt.args.q, tt.args.variables)
 			if (err != nil) != tt.wantReadErr {
@@ -67,3 +133,36 @@ func TestCache_Write_Read_Query(t *testing.T) {
 		})
 	}
 }
+
+func TestGithubFetcher_Query_Cached(t *testing.T) {
+	defer os.Setenv("XDG_CACHE_HOME", os.Getenv("XDG_CACHE_HOME"))
+	os.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"user": {"login": "someone", "location": "Hamburg"}}}`))
+	}))
+	defer srv.Close()
+
+	c, err := cache.NewCache("fetcher", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	f := fetch.GithubFetcher{Client: githubv4.NewEnterpriseClient(srv.URL, srv.Client()), Cache: c}
+	for i := 0; i < 2; i++ {
+		u, err := f.GetUser(context.Background(), "someone")
+		if err != nil {
+			t.Fatalf("GetUser()\nerror: %v", err)
+		}
+		if u.Location != "Hamburg" {
+			t.Errorf("GetUser() #%d = %+v", i+1, u)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("GetUser() twice made %d requests, the second one should have hit the cache", requests)
+	}
+}
@@ -0,0 +1,121 @@
+package test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/fetch"
+	"github.com/shurcooL/githubv4"
+)
+
+func TestSet_Merge(t *testing.T) {
+	alice := fetch.User{ID: "1", Login: "alice", Location: "Berlin"}
+	bob := fetch.User{ID: "2", Login: "bob", Location: "Hamburg"}
+
+	hcl, cty := candidate.NewSet(), candidate.NewSet()
+	hcl.Add(alice, candidate.Interaction{Repo: "hashicorp/hcl", Role: candidate.Forker})
+	hcl.Add(bob, candidate.Interaction{Repo: "hashicorp/hcl", Role: candidate.Reviewer, URL: "u1"})
+	cty.Add(alice, candidate.Interaction{Repo: "zclconf/go-cty", Role: candidate.Committer})
+	cty.Add(alice, candidate.Interaction{Repo: "zclconf/go-cty", Role: candidate.Committer})
+
+	merged := candidate.NewSet()
+	merged.Merge(hcl)
+	merged.Merge(cty)
+
+	if merged.Len() != 2 {
+		t.Fatalf("merged set has %d candidates, want 2", merged.Len())
+	}
+	c, ok := merged.Get("alice")
+	if !ok {
+		t.Fatal("alice is missing")
+	}
+	if repos := c.Repos(); !reflect.DeepEqual(repos, []string{"hashicorp/hcl", "zclconf/go-cty"}) {
+		t.Errorf("Repos() = %v", repos)
+	}
+	if len(c.Interactions) != 2 {
+		t.Errorf("duplicate interactions weren't merged: %v", c.Interactions)
+	}
+	if want := "hashicorp/hcl: forker; zclconf/go-cty: committer"; c.Summary() != want {
+		t.Errorf("Summary() = %s\nwant %s", c.Summary(), want)
+	}
+}
+
+func TestSet_Put(t *testing.T) {
+	activity := &fetch.Activity{}
+	activity.Contributions.TotalCommitContributions = 42
+	c := &candidate.Candidate{
+		User:         fetch.User{ID: "1", Login: "alice"},
+		Interactions: []candidate.Interaction{{Repo: "hashicorp/hcl", Role: candidate.Committer}},
+		Activity:     activity,
+		Languages:    fetch.LanguageProfile{{Name: "Go", Share: 1}},
+	}
+
+	s := candidate.NewSet()
+	s.Put(c)
+	merged := candidate.NewSet()
+	merged.Merge(s)
+
+	all := merged.All()
+	if len(all) != 1 {
+		t.Fatalf("All() = %v, want a single candidate", all)
+	}
+	if all[0].Activity != activity || all[0].Languages.Rank("go") != 1 {
+		t.Errorf("Put() lost the enrichments: %+v", all[0])
+	}
+}
+
+func TestRepoFilter_Match(t *testing.T) {
+	repo := func(lang string, stars int, archived, fork bool, pushed time.Time) fetch.Repo {
+		r := fetch.Repo{IsArchived: githubv4.Boolean(archived), IsFork: githubv4.Boolean(fork)}
+		r.PrimaryLanguage.Name = githubv4.String(lang)
+		r.Stargazers.TotalCount = githubv4.Int(stars)
+		r.PushedAt = githubv4.DateTime{Time: pushed}
+		return r
+	}
+	recently := time.Now().Add(-24 * time.Hour)
+	filter := fetch.RepoFilter{Language: "go", MinStars: 10, PushedWithin: 30 * 24 * time.Hour}
+
+	tests := []struct {
+		name string
+		repo fetch.Repo
+		want bool
+	}{
+		{"matching", repo("Go", 20, false, false, recently), true},
+		{"other language", repo("HCL", 20, false, false, recently), false},
+		{"too few stars", repo("Go", 5, false, false, recently), false},
+		{"archived", repo("Go", 20, true, false, recently), false},
+		{"fork", repo("Go", 20, false, true, recently), false},
+		{"stale", repo("Go", 20, false, false, recently.AddDate(-1, 0, 0)), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filter.Match(tt.repo); got != tt.want {
+				t.Errorf("RepoFilter.Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSet_InRepos(t *testing.T) {
+	s := candidate.NewSet()
+	s.Add(fetch.User{Login: "one"}, candidate.Interaction{Repo: "a/a", Role: candidate.Forker})
+	s.Add(fetch.User{Login: "three"},
+		candidate.Interaction{Repo: "a/a", Role: candidate.Reviewer},
+		candidate.Interaction{Repo: "b/b", Role: candidate.Commenter},
+		candidate.Interaction{Repo: "c/c", Role: candidate.Committer})
+	s.Add(fetch.User{Login: "two"},
+		candidate.Interaction{Repo: "a/a", Role: candidate.Forker},
+		candidate.Interaction{Repo: "a/a", Role: candidate.Stargazer},
+		candidate.Interaction{Repo: "c/c", Role: candidate.Stargazer})
+
+	var got []string
+	for _, c := range s.InRepos(2) {
+		got = append(got, c.Login())
+	}
+	if want := []string{"three", "two"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("InRepos(2) = %v\nwant %v", got, want)
+	}
+}
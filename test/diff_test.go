@@ -0,0 +1,86 @@
+package test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/fetch"
+	"github.com/florinutz/gh-recruiter/state"
+	"github.com/shurcooL/githubv4"
+)
+
+func TestDiff(t *testing.T) {
+	person := func(id, login, location string, hireable bool) *candidate.Candidate {
+		return &candidate.Candidate{User: fetch.User{ID: id, Login: githubv4.String(login),
+			Location: githubv4.String(location), IsHireable: githubv4.Boolean(hireable)}}
+	}
+
+	before := []*candidate.Candidate{
+		person("1", "mover", "Hamburg", false),
+		person("2", "gone", "Berlin", false),
+		person("3", "same", "Berlin", true),
+	}
+	after := []*candidate.Candidate{
+		person("3", "same", "Berlin", true),
+		person("1", "mover-renamed", "Berlin", true),
+		person("4", "newcomer", "Berlin", false),
+	}
+
+	want := []candidate.Change{
+		{Kind: candidate.Changed, Login: "mover-renamed", Field: "hireable", Before: "false", After: "true"},
+		{Kind: candidate.Changed, Login: "mover-renamed", Field: "location", Before: "Hamburg", After: "Berlin"},
+		{Kind: candidate.Changed, Login: "mover-renamed", Field: "login", Before: "mover", After: "mover-renamed"},
+		{Kind: candidate.Added, Login: "newcomer"},
+		{Kind: candidate.Dropped, Login: "gone"},
+	}
+	if got := candidate.Diff(before, after); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %+v\nwant %+v", got, want)
+	}
+}
+
+func TestStore_Runs(t *testing.T) {
+	s, err := state.NewStoreAt(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	started := time.Date(2018, 11, 20, 9, 0, 0, 0, time.UTC)
+	for _, at := range []time.Time{started.AddDate(0, 0, 7), started} {
+		r := state.NewRun("repo", []string{"hashicorp", "hcl"}, nil, at)
+		r.AddRepo("hashicorp/hcl")
+		r.AddRepo("hashicorp/hcl")
+		r.Candidates = []*candidate.Candidate{{User: fetch.User{Login: "someone"}}}
+		if err = s.SaveRun(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ids, err := s.RunIDs()
+	if want := []string{"20181120-090000", "20181127-090000"}; err != nil || !reflect.DeepEqual(ids, want) {
+		t.Fatalf("RunIDs() = %v, %v\nwant %v", ids, err, want)
+	}
+	r, err := s.LoadRun(ids[0])
+	if err != nil || len(r.Repos) != 1 || r.Candidates[0].Login() != "someone" {
+		t.Errorf("LoadRun() = %+v, %v", r, err)
+	}
+	if _, err = s.LoadRun("nope"); err == nil {
+		t.Errorf("LoadRun() of a missing run didn't fail")
+	}
+
+	same := state.NewRun("repo", nil, nil, started.Add(time.Millisecond))
+	for i := 0; i < 2; i++ {
+		if err = s.SaveRun(same); err != nil || same.ID != "20181120-090000-2" {
+			t.Errorf("SaveRun() of a run started in the same second = %s, %v\nwant 20181120-090000-2", same.ID, err)
+		}
+	}
+
+	pruned, err := s.PruneRuns(started.AddDate(0, 0, 1))
+	if want := []string{"run-20181120-090000", "run-20181120-090000-2"}; err != nil || !reflect.DeepEqual(pruned, want) {
+		t.Errorf("PruneRuns() = %v, %v\nwant %v", pruned, err, want)
+	}
+	if ids, err = s.RunIDs(); err != nil || !reflect.DeepEqual(ids, []string{"20181127-090000"}) {
+		t.Errorf("RunIDs() after pruning = %v, %v", ids, err)
+	}
+}
@@ -0,0 +1,60 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/fetch"
+	"github.com/florinutz/gh-recruiter/filter"
+	"github.com/florinutz/gh-recruiter/state"
+	"github.com/shurcooL/githubv4"
+)
+
+func TestStore_DoNotContact(t *testing.T) {
+	s, err := state.NewStoreAt(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := s.LoadDoNotContact()
+	if err != nil || len(list.Entries) != 0 {
+		t.Fatalf("LoadDoNotContact() with nothing stored = %+v, error %v", list, err)
+	}
+
+	list.Add(state.DoNotContactEntry{Login: "Renamed", ID: "MDQ6VXNlcjE=", Reason: "asked", Added: time.Now()})
+	list.Add(state.DoNotContactEntry{Login: "loginonly"})
+	list.Add(state.DoNotContactEntry{Login: "renamed", ID: "MDQ6VXNlcjE=", Reason: "asked twice"})
+	if err = s.SaveDoNotContact(list); err != nil {
+		t.Fatal(err)
+	}
+	if list, err = s.LoadDoNotContact(); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Entries) != 2 {
+		t.Fatalf("Add() didn't replace the existing entry: %+v", list.Entries)
+	}
+
+	f := filter.DoNotContact{List: list}
+	tests := []struct {
+		name, login, id string
+		want            bool
+	}{
+		{"stranger", "someone", "MDQ6VXNlcjI=", true},
+		{"renamed login", "new-login", "MDQ6VXNlcjE=", false},
+		{"login", "LoginOnly", "MDQ6VXNlcjM=", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &candidate.Candidate{User: fetch.User{Login: githubv4.String(tt.login), ID: tt.id}}
+			if ok, reason := f.Match(context.Background(), c); ok != tt.want {
+				t.Errorf("DoNotContact.Match() = %v (%s), want %v", ok, reason, tt.want)
+			}
+		})
+	}
+
+	if !list.Remove("MDQ6VXNlcjE=") || list.Remove("nobody") || len(list.Entries) != 1 {
+		t.Errorf("Remove() left %+v", list.Entries)
+	}
+}
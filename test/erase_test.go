@@ -0,0 +1,98 @@
+package test
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/cache"
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/state"
+)
+
+type cachedUser struct {
+	Login string
+	ID    string
+}
+
+func TestCache_Erase_Prune(t *testing.T) {
+	defer os.Setenv("XDG_CACHE_HOME", os.Getenv("XDG_CACHE_HOME"))
+	os.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	c, err := cache.NewCache("erase", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	users := []cachedUser{{"Someone", "MDQ6VXNlcjE="}, {"other", "MDQ6VXNlcjI="}, {"renamed", "MDQ6VXNlcjM="}}
+	for _, u := range users {
+		if err = c.WriteQuery(u, map[string]interface{}{"login": u.Login}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	erased, err := c.Erase([]string{"someone"}, []string{"MDQ6VXNlcjM="})
+	if err != nil || erased != 2 {
+		t.Errorf("Erase() = %d, %v\nwant 2", erased, err)
+	}
+	if got, err := c.ReadQuery(users[1], map[string]interface{}{"login": "other"}); err != nil ||
+		!reflect.DeepEqual(got, users[1]) {
+		t.Errorf("ReadQuery() after Erase() = %v, %v", got, err)
+	}
+
+	if pruned, err := c.Prune(time.Hour); err != nil || pruned != 0 {
+		t.Errorf("Prune(1h) = %d, %v\nwant 0", pruned, err)
+	}
+	if pruned, err := c.Prune(0); err != nil || pruned != 1 {
+		t.Errorf("Prune(0) = %d, %v\nwant 1", pruned, err)
+	}
+}
+
+func TestStore_Erase(t *testing.T) {
+	s, err := state.NewStoreAt(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	marks := &state.Marks{Owner: "hashicorp", Name: "hcl", Seen: map[string]bool{"Someone": true, "other": true}}
+	checkpoint := state.NewCheckpoint("hashicorp", "hcl", "forkers")
+	checkpoint.Logins["forker"] = []string{"other", "someone"}
+	checkpoint.Interactions = []state.Interaction{
+		{Login: "other", Role: candidate.Commenter, URL: "https://github.com/hashicorp/hcl/pull/1", Author: "Someone"},
+		{Login: "other", Role: candidate.Reviewer, URL: "https://github.com/hashicorp/hcl/pull/2", Author: "third"},
+	}
+	untouched := state.NewCheckpoint("hashicorp", "hcl", "prs")
+	untouched.Logins["reviewer"] = []string{"other"}
+	for _, err := range []error{s.SaveMarks(marks), s.SaveCheckpoint(checkpoint), s.SaveCheckpoint(untouched)} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	erased, err := s.Erase("someone")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{state.MarksKey("hashicorp", "hcl"), checkpoint.Key()}
+	if !reflect.DeepEqual(erased, want) {
+		t.Errorf("Erase() = %v\nwant %v", erased, want)
+	}
+
+	if marks, err = s.LoadMarks("hashicorp", "hcl"); err != nil || marks.Seen["Someone"] || !marks.Seen["other"] {
+		t.Errorf("marks after Erase() = %+v, %v", marks, err)
+	}
+	if checkpoint, err = s.LoadCheckpoint("hashicorp", "hcl", "forkers"); err != nil ||
+		!reflect.DeepEqual(checkpoint.Logins["forker"], []string{"other"}) ||
+		len(checkpoint.Interactions) != 1 || checkpoint.Interactions[0].Author != "third" {
+		t.Errorf("checkpoint after Erase() = %+v, %v", checkpoint, err)
+	}
+
+	if err = s.LogErasure(state.Erasure{Subject: "someone", StateKeys: erased}); err != nil {
+		t.Fatal(err)
+	}
+	if erasures, err := s.LoadErasures(); err != nil || len(erasures) != 1 {
+		t.Errorf("LoadErasures() = %v, %v", erasures, err)
+	}
+}
@@ -0,0 +1,66 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/fetch"
+	"github.com/florinutz/gh-recruiter/filter"
+	"github.com/shurcooL/githubv4"
+)
+
+// employee returns a user working at the given company and member of the given orgs
+func employee(company string, orgs ...string) fetch.User {
+	u := fetch.User{Login: "someone", Company: githubv4.String(company)}
+	for _, org := range orgs {
+		u.Organizations.Nodes = append(u.Organizations.Nodes, fetch.Organization{Login: githubv4.String(org)})
+	}
+
+	return u
+}
+
+func TestExclusion(t *testing.T) {
+	exclusion, err := filter.NewExclusion([]string{"@Acme"}, []string{`^@?acme\b`, `friendly corp`})
+	if err != nil {
+		t.Fatalf("NewExclusion()\nerror: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		user fetch.User
+		want bool
+	}{
+		{"nobody", employee(""), true},
+		{"other org", employee("", "hashicorp"), true},
+		{"org member", employee("", "hashicorp", "acme"), false},
+		{"company mention", employee("@ACME"), false},
+		{"company name", employee("Friendly Corp GmbH"), false},
+		{"company prefix only", employee("Acmeville"), true},
+	}
+
+	var audited []string
+	f := filter.Audited{
+		Filter: exclusion,
+		Audit:  func(c *candidate.Candidate, reason string) { audited = append(audited, c.Login()) },
+	}
+	rejected := 0
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, reason := f.Match(context.Background(), &candidate.Candidate{User: tt.user})
+			if ok != tt.want {
+				t.Errorf("Exclusion.Match() = %v (%s), want %v", ok, reason, tt.want)
+			}
+			if !ok {
+				rejected++
+			}
+		})
+	}
+	if len(audited) != rejected {
+		t.Errorf("Audited reported %d users, want %d", len(audited), rejected)
+	}
+
+	if _, err := filter.NewExclusion(nil, []string{"("}); err == nil {
+		t.Errorf("NewExclusion() accepted an invalid pattern")
+	}
+}
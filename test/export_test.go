@@ -0,0 +1,150 @@
+package test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/export"
+	"github.com/florinutz/gh-recruiter/fetch"
+)
+
+func TestExporter(t *testing.T) {
+	r := &candidate.Record{
+		Candidate: candidate.Candidate{
+			User: fetch.User{Login: "someone", Name: "Ada King Lovelace", Email: "ada@example.com",
+				Company: "Analytical, Ltd", Location: "London"},
+			Interactions: []candidate.Interaction{{Repo: "hashicorp/hcl", Role: candidate.Reviewer}},
+		},
+		Status: candidate.Contacted,
+		Tags:   []string{"go", "senior"},
+	}
+
+	tests := []struct {
+		name    string
+		profile string
+		want    []string
+	}{
+		{
+			name:    "greenhouse",
+			profile: "greenhouse",
+			want:    []string{"First Name,Last Name,Company,Email", "Ada King,Lovelace,\"Analytical, Ltd\",ada@example.com"},
+		},
+		{
+			name:    "lever",
+			profile: "lever",
+			want:    []string{"Ada King Lovelace,ada@example.com", "https://github.com/someone,GitHub,\"go,senior\""},
+		},
+		{
+			name:    "vcard",
+			profile: "vcard",
+			want: []string{"BEGIN:VCARD\r\nVERSION:3.0\r\nFN:Ada King Lovelace\r\nN:Lovelace;Ada King;;;\r\n",
+				"ORG:Analytical\\, Ltd\r\n", "ADR;TYPE=HOME:;;;London;;;\r\n", "CATEGORIES:go,senior\r\nEND:VCARD\r\n"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			profile, ok := export.Find(test.profile, nil)
+			if !ok {
+				t.Fatalf("Find(%s) found nothing", test.profile)
+			}
+			e, err := export.New(profile)
+			if err != nil {
+				t.Fatalf("New()\nerror: %v", err)
+			}
+			var b bytes.Buffer
+			if err = e.Write(&b, []*candidate.Record{r}, time.Now()); err != nil {
+				t.Fatalf("Write()\nerror: %v", err)
+			}
+			for _, want := range test.want {
+				if !strings.Contains(b.String(), want) {
+					t.Errorf("Write() lacks %q:\n%s", want, b.String())
+				}
+			}
+		})
+	}
+}
+
+func TestExporter_VCard(t *testing.T) {
+	r := &candidate.Record{
+		Candidate: candidate.Candidate{
+			User: fetch.User{Login: "someone", Name: "Zoë Jäger", Location: "Berlin; Mitte"},
+			Interactions: []candidate.Interaction{
+				{Repo: "hashicorp/hcl", Role: candidate.Reviewer},
+				{Repo: "zclconf/go-cty", Role: candidate.Committer},
+				{Repo: "hashicorp/terraform-plugin-sdk", Role: candidate.Commenter},
+			},
+		},
+	}
+
+	profile, _ := export.Find("vcard", nil)
+	e, err := export.New(profile)
+	if err != nil {
+		t.Fatalf("New()\nerror: %v", err)
+	}
+	var b bytes.Buffer
+	if err = e.Write(&b, []*candidate.Record{r}, time.Now()); err != nil {
+		t.Fatalf("Write()\nerror: %v", err)
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("Write() line of %d octets: %q", len(line), line)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("Write() split a character: %q", line)
+		}
+	}
+
+	unfolded := strings.ReplaceAll(b.String(), "\r\n ", "")
+	for _, want := range []string{
+		"FN:Zoë Jäger\r\n",
+		"ADR;TYPE=HOME:;;;Berlin\\; Mitte;;;\r\n",
+		"NOTE:hashicorp/hcl: reviewer\\; hashicorp/terraform-plugin-sdk: commenter\\; zclconf/go-cty: committer\r\n",
+	} {
+		if !strings.Contains(unfolded, want) {
+			t.Errorf("Write() lacks %q:\n%s", want, unfolded)
+		}
+	}
+}
+
+func TestExporter_Configured(t *testing.T) {
+	configured := []export.Profile{{
+		Name:   "lever",
+		Format: export.FormatCsv,
+		Columns: []export.Column{
+			{Header: "Who", Value: "{{.Login}} ({{.Status}})"},
+			{Header: "Where", Value: "{{.Location}}"},
+		},
+	}}
+
+	profile, _ := export.Find("lever", configured)
+	e, err := export.New(profile)
+	if err != nil {
+		t.Fatalf("New()\nerror: %v", err)
+	}
+	r := &candidate.Record{Candidate: candidate.Candidate{User: fetch.User{Login: "someone"}}, Status: candidate.New}
+	var b bytes.Buffer
+	if err = e.Write(&b, []*candidate.Record{r}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&b).ReadAll()
+	if err != nil || len(rows) != 2 || rows[0][0] != "Who" || rows[1][0] != "someone (new)" {
+		t.Errorf("Write() with a configured profile = %v, %v", rows, err)
+	}
+
+	if names := export.Names(configured); strings.Join(names, " ") != "greenhouse lever vcard" {
+		t.Errorf("Names() = %v", names)
+	}
+	if _, err = export.New(export.Profile{Name: "broken", Format: export.FormatCsv,
+		Columns: []export.Column{{Header: "x", Value: "{{.Nope"}}}); err == nil {
+		t.Error("New() with a broken template returned no error")
+	}
+	if _, err = export.New(export.Profile{Name: "pdf", Format: "pdf"}); err == nil {
+		t.Error("New() with an unknown format returned no error")
+	}
+}
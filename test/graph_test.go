@@ -0,0 +1,75 @@
+package test
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/florinutz/gh-recruiter/graph"
+)
+
+func collaborationGraph() *graph.Graph {
+	g := graph.New()
+	g.Add("bob", "alice", graph.Reviewed)
+	g.Add("bob", "alice", graph.Reviewed)
+	g.Add("carol", "alice", graph.Commented)
+	g.Add("alice", "bob", graph.Reviewed)
+	g.Add("dave", "dave", graph.Commented) // self loops don't count
+	g.Add("", "alice", graph.Commented)    // neither do ghosts
+
+	return g
+}
+
+func TestGraph_Metrics(t *testing.T) {
+	g := collaborationGraph()
+
+	if nodes := g.Nodes(); len(nodes) != 3 {
+		t.Fatalf("Nodes() = %v", nodes)
+	}
+	if edges := g.Edges(); len(edges) != 3 || edges[1].Weight != 2 {
+		t.Errorf("Edges() = %v", edges)
+	}
+
+	metrics := g.Metrics()
+	if metrics["alice"].Degree != 2 || metrics["carol"].Degree != 1 {
+		t.Errorf("degrees: %+v", metrics)
+	}
+
+	total := 0.0
+	for _, m := range metrics {
+		total += m.PageRank
+	}
+	if math.Abs(total-1) > 1e-9 {
+		t.Errorf("PageRanks add up to %f", total)
+	}
+	if metrics["alice"].PageRank <= metrics["bob"].PageRank || metrics["bob"].PageRank <= metrics["carol"].PageRank {
+		t.Errorf("unexpected PageRank order: %+v", metrics)
+	}
+}
+
+func TestGraph_Export(t *testing.T) {
+	g := collaborationGraph()
+
+	var dot, graphML, json bytes.Buffer
+	if err := g.WriteDOT(&dot); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.WriteGraphML(&graphML); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.WriteJSON(&json); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, check := range map[string]bool{
+		"dot edge":      strings.Contains(dot.String(), `"bob" -> "alice" [label="reviewed", weight=2`),
+		"graphml edge":  strings.Contains(graphML.String(), `<edge source="carol" target="alice">`),
+		"graphml attrs": strings.Contains(graphML.String(), `<key id="pagerank" for="node"`),
+		"json node":     strings.Contains(json.String(), `"ID": "alice"`),
+	} {
+		if !check {
+			t.Errorf("%s missing from the export", name)
+		}
+	}
+}
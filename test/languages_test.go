@@ -0,0 +1,86 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/fetch"
+	"github.com/florinutz/gh-recruiter/filter"
+	"github.com/shurcooL/githubv4"
+)
+
+// langRepo builds a repo from language name - size pairs, the first one being the primary language
+func langRepo(sizes ...interface{}) fetch.LangRepo {
+	var r fetch.LangRepo
+	for i := 0; i < len(sizes); i += 2 {
+		name := githubv4.String(sizes[i].(string))
+		if i == 0 {
+			r.PrimaryLanguage.Name = &name
+		}
+		size := githubv4.Int(sizes[i+1].(int))
+		r.Languages.TotalSize += size
+		r.Languages.Edges = append(r.Languages.Edges, struct {
+			Size githubv4.Int
+			Node fetch.LangFragment
+		}{Size: size, Node: fetch.LangFragment{Name: &name}})
+	}
+
+	return r
+}
+
+func TestNewLanguageProfile(t *testing.T) {
+	owned := []fetch.LangRepo{langRepo("Go", 900, "Makefile", 100), langRepo("Go", 1)}
+	contributed := []fetch.LangRepo{langRepo("Rust", 500, "Go", 500), langRepo("HCL", 0)}
+
+	profile := fetch.NewLanguageProfile(owned, contributed)
+
+	if top := profile.Top(3); !reflect.DeepEqual(top, []string{"Go", "HCL", "Rust"}) {
+		t.Errorf("Top(3) = %v", top)
+	}
+	// Go: .9 + 1 + .25, Makefile: .1, Rust: .25, HCL: .5 (primary language only), out of 3
+	if math.Abs(profile[0].Share-2.15/3) > 1e-9 {
+		t.Errorf("Go's share = %f", profile[0].Share)
+	}
+	if profile.Rank("go") != 1 || profile.Rank("makefile") != 4 || profile.Rank("java") != 0 {
+		t.Errorf("unexpected ranks in %v", profile)
+	}
+}
+
+func TestLanguagesFilter(t *testing.T) {
+	profile := fetch.NewLanguageProfile([]fetch.LangRepo{
+		langRepo("Java", 10), langRepo("Java", 10), langRepo("Kotlin", 10), langRepo("Go", 5, "Shell", 1),
+	}, nil)
+	fetcher := func(ctx context.Context, login string) (fetch.LanguageProfile, error) {
+		if login == "ghost" {
+			return nil, errors.New("not found")
+		}
+		return profile, nil
+	}
+
+	tests := []struct {
+		name   string
+		login  string
+		filter filter.Languages
+		want   bool
+	}{
+		{"enrich only", "alice", filter.Languages{Fetch: fetcher}, true},
+		{"enrich only, failing", "ghost", filter.Languages{Fetch: fetcher}, true},
+		{"in top 3", "alice", filter.Languages{Fetch: fetcher, Require: "go", Top: 3}, true},
+		{"not in top 2", "alice", filter.Languages{Fetch: fetcher, Require: "go", Top: 2}, false},
+		{"missing", "alice", filter.Languages{Fetch: fetcher, Require: "rust", Top: 3}, false},
+		{"required but failing", "ghost", filter.Languages{Fetch: fetcher, Require: "go", Top: 3}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &candidate.Candidate{User: fetch.User{Login: githubv4.String(tt.login)}}
+			if ok, reason := tt.filter.Match(context.Background(), c); ok != tt.want {
+				t.Errorf("Languages.Match() = %v (%s), want %v", ok, reason, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/fetch"
+	"github.com/florinutz/gh-recruiter/notify"
+	"github.com/shurcooL/githubv4"
+)
+
+func TestRecord_Refresh_Notify(t *testing.T) {
+	var received []notify.Notification
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("X-Token") != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		var n notify.Notification
+		if err := json.NewDecoder(r.Body).Decode(&n); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received = append(received, n)
+	}))
+	defer hook.Close()
+
+	file := filepath.Join(t.TempDir(), "notifications.jsonl")
+	sinks := notify.Sinks{
+		notify.Webhook{URL: hook.URL, Headers: map[string]string{"X-Token": "secret"}},
+		&notify.File{Path: file},
+	}
+
+	db := &candidate.DB{}
+	r := db.Track(&candidate.Candidate{User: fetch.User{Login: "someone", Company: "ACME"}}, time.Now())
+	refreshed := fetch.User{Login: "someone", Company: "Initech", IsHireable: true, Location: githubv4.String("Berlin")}
+
+	changes := r.Refresh(refreshed, time.Now(), "hireable", "company")
+	if len(changes) != 2 {
+		t.Fatalf("Refresh() = %+v\nwant the hireable and company changes", changes)
+	}
+	for _, change := range changes {
+		if err := sinks.Notify(context.Background(), notify.Notification{Change: change, At: time.Now()}); err != nil {
+			t.Errorf("Notify()\nerror: %v", err)
+		}
+	}
+	if r.Candidate.User.Location != "Berlin" || r.History[len(r.History)-1].Kind != candidate.EventChanged {
+		t.Errorf("Refresh() didn't update the record: %+v", r)
+	}
+
+	if len(received) != 2 || received[0].Field != "hireable" || received[1].After != "Initech" {
+		t.Errorf("webhook received %+v", received)
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	lines := 0
+	for scanner := bufio.NewScanner(f); scanner.Scan(); lines++ {
+	}
+	if lines != 2 {
+		t.Errorf("file sink wrote %d lines, want 2", lines)
+	}
+
+	rejecting := notify.Webhook{URL: hook.URL}
+	if err := rejecting.Notify(context.Background(), notify.Notification{}); err == nil {
+		t.Errorf("Notify() ignored the webhook's error status")
+	}
+}
+
+func TestFile_Erase_Prune(t *testing.T) {
+	f := &notify.File{Path: filepath.Join(t.TempDir(), "notifications.jsonl")}
+	if erased, err := f.Erase("someone"); err != nil || erased != 0 {
+		t.Errorf("Erase() on a missing file = %d, %v\nwant 0", erased, err)
+	}
+
+	old := time.Now().AddDate(0, -2, 0)
+	notifications := []notify.Notification{
+		{Change: candidate.Change{Kind: candidate.Changed, Login: "Someone", Field: "company", After: "ACME"}, At: time.Now()},
+		{Change: candidate.Change{Kind: candidate.Changed, Login: "renamed", Field: "login", Before: "someone", After: "renamed"}, At: time.Now()},
+		{Change: candidate.Change{Kind: candidate.Added, Login: "other", After: "hashicorp/hcl"}, At: old},
+		{Change: candidate.Change{Kind: candidate.Added, Login: "third", After: "hashicorp/hcl"}, At: time.Now()},
+	}
+	for _, n := range notifications {
+		if err := f.Notify(context.Background(), n); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if erased, err := f.Erase("someone"); err != nil || erased != 2 {
+		t.Errorf("Erase() = %d, %v\nwant 2", erased, err)
+	}
+	if pruned, err := f.Prune(time.Now().AddDate(0, -1, 0)); err != nil || pruned != 1 {
+		t.Errorf("Prune() = %d, %v\nwant 1", pruned, err)
+	}
+
+	content, err := ioutil.ReadFile(f.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var left notify.Notification
+	if err = json.Unmarshal(content, &left); err != nil || left.Login != "third" {
+		t.Errorf("file after Erase() and Prune() = %s, %v", content, err)
+	}
+}
+
+func TestNotification_String(t *testing.T) {
+	at := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		change candidate.Change
+		want   string
+	}{
+		{"new in a repo", candidate.Change{Kind: candidate.Added, Login: "alice", After: "hashicorp/hcl"},
+			"2021-03-01T12:00:00Z alice: new candidate in hashicorp/hcl"},
+		{"new", candidate.Change{Kind: candidate.Added, Login: "alice"}, "2021-03-01T12:00:00Z alice: new candidate"},
+		{"dropped", candidate.Change{Kind: candidate.Dropped, Login: "alice"},
+			"2021-03-01T12:00:00Z alice: no longer a candidate"},
+		{"changed", candidate.Change{Kind: candidate.Changed, Login: "alice", Field: "company", Before: "ACME", After: "Initech"},
+			`2021-03-01T12:00:00Z alice: company changed from "ACME" to "Initech"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (notify.Notification{Change: tt.change, At: at}).String(); got != tt.want {
+				t.Errorf("String() = %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
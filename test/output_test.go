@@ -0,0 +1,54 @@
+package test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/florinutz/gh-recruiter/output"
+)
+
+type row []string
+
+func (r row) FormatForCsv() []string {
+	return r
+}
+
+func TestOutput_Formats(t *testing.T) {
+	header := []string{"Login", "Bio"}
+	rows := []row{{"alice", "gopher\nfrom Berlin"}, {"bob", "rustacean"}}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{output.FormatCsv, "Login,Bio\nalice,\"gopher\nfrom Berlin\"\nbob,rustacean\n"},
+		{output.FormatJSON, "[\"alice\",\"gopher\\nfrom Berlin\"]\n[\"bob\",\"rustacean\"]\n"},
+		{output.FormatTable, "Login  Bio\nalice  gopher from Berlin\nbob    rustacean\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := output.New(tt.format, &buf, header)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, r := range rows {
+				if err = w.Write(r); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err = w.Flush(); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+
+	if _, err := output.New("yaml", &bytes.Buffer{}, header); err == nil || !strings.Contains(err.Error(), "yaml") {
+		t.Errorf("New() with an unknown format: error %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/fetch"
+	"github.com/florinutz/gh-recruiter/outreach"
+)
+
+func TestRender(t *testing.T) {
+	c := &candidate.Candidate{
+		User: fetch.User{Login: "someone", Name: "Ada Lovelace", Email: "ada@example.com"},
+		Interactions: []candidate.Interaction{
+			{Repo: "zclconf/go-cty", Role: candidate.Forker},
+			{Repo: "hashicorp/hcl", Role: candidate.Reviewer, URL: "https://github.com/hashicorp/hcl/pull/1#pullrequestreview-1"},
+			{Repo: "hashicorp/hcl", Role: candidate.Commenter, URL: "https://github.com/hashicorp/hcl/pull/2#issuecomment-2"},
+		},
+	}
+
+	tmpl, err := outreach.Parse("msg.tmpl", `Hi {{.FirstName}},
+{{range .Reviews}}
+your review {{.URL}} on {{.Repo}} was great.{{end}}`)
+	if err != nil {
+		t.Fatalf("Parse()\nerror: %v", err)
+	}
+	draft, err := outreach.Render(tmpl, c)
+	if err != nil {
+		t.Fatalf("Render()\nerror: %v", err)
+	}
+
+	if draft.Subject != "Your work on hashicorp/hcl" {
+		t.Errorf("Subject = %q", draft.Subject)
+	}
+	if !strings.HasPrefix(draft.Body, "Hi Ada,") || !strings.Contains(draft.Body, "pullrequestreview-1 on hashicorp/hcl") {
+		t.Errorf("Body = %q", draft.Body)
+	}
+
+	eml := string(draft.EML("recruiter@example.com", time.Now()))
+	for _, header := range []string{`To: "Ada Lovelace" <ada@example.com>`, "X-Unsent: 1", "Subject: Your work"} {
+		if !strings.Contains(eml, header) {
+			t.Errorf("EML() lacks %q:\n%s", header, eml)
+		}
+	}
+	if md := string(draft.Markdown()); !strings.HasPrefix(md, "# Your work on hashicorp/hcl") {
+		t.Errorf("Markdown() = %q", md)
+	}
+
+	custom, err := outreach.Parse("custom", `{{define "subject"}}About {{.Login}}{{end}}hello`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if draft, err = outreach.Render(custom, c); err != nil || draft.Subject != "About someone" {
+		t.Errorf("Render() with a custom subject = %+v, %v", draft, err)
+	}
+}
@@ -0,0 +1,77 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/florinutz/gh-recruiter/fetch"
+	"github.com/shurcooL/githubv4"
+)
+
+// fakeConnection serves pages of a connection of the given size, failing at failAt (if > 0)
+func fakeConnection(pages int, failAt int, calls *int) fetch.PageFunc {
+	return func(ctx context.Context, after *githubv4.String) (fetch.PageInfo, error) {
+		*calls++
+		if failAt > 0 && *calls == failAt {
+			return fetch.PageInfo{}, errors.New("boom")
+		}
+		return fetch.PageInfo{
+			EndCursor:   githubv4.String(string(rune('a' + *calls - 1))),
+			HasNextPage: githubv4.Boolean(*calls < pages),
+		}, nil
+	}
+}
+
+func TestPaginator_Each(t *testing.T) {
+	tests := []struct {
+		name       string
+		pages      int
+		failAt     int
+		maxPages   int
+		wantCalls  int
+		wantCursor string
+		wantErr    bool
+		// wantExhausted is whether the walk got to the connection's end
+		wantExhausted bool
+	}{
+		{"all pages", 3, 0, 0, 3, "c", false, true},
+		{"max pages", 5, 0, 2, 2, "b", false, false},
+		{"max pages at the end", 3, 0, 3, 3, "c", false, true},
+		{"error keeps last cursor", 5, 3, 0, 3, "b", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls, seen := 0, 0
+			p := &fetch.Paginator{MaxPages: tt.maxPages, OnPage: func(*githubv4.String) { seen++ }}
+
+			err := p.Each(context.Background(), fakeConnection(tt.pages, tt.failAt, &calls))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Paginator.Each()\nerror: %v\nwantErr %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("Paginator.Each() made %d calls, want %d", calls, tt.wantCalls)
+			}
+			if seen != p.Pages() {
+				t.Errorf("OnPage called %d times for %d pages", seen, p.Pages())
+			}
+			if p.Exhausted() != tt.wantExhausted {
+				t.Errorf("Paginator.Exhausted() = %v\nwant %v", p.Exhausted(), tt.wantExhausted)
+			}
+			if p.After == nil || string(*p.After) != tt.wantCursor {
+				t.Errorf("Paginator.After = %v\nwant %s", p.After, tt.wantCursor)
+			}
+		})
+	}
+}
+
+func TestPaginator_Each_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+	if err := fetch.NewPaginator(nil).Each(ctx, fakeConnection(3, 0, &calls)); err == nil || calls != 0 {
+		t.Errorf("Paginator.Each() on a cancelled context: error %v, %d calls", err, calls)
+	}
+}
@@ -0,0 +1,78 @@
+package test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/fetch"
+	"github.com/florinutz/gh-recruiter/state"
+	"github.com/shurcooL/githubv4"
+)
+
+func TestDB_Track(t *testing.T) {
+	s, err := state.NewStoreAt(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := s.LoadCandidates()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := time.Date(2018, 11, 1, 0, 0, 0, 0, time.UTC)
+	found := &candidate.Candidate{
+		User:         fetch.User{ID: "MDQ6VXNlcjE=", Login: "someone"},
+		Interactions: []candidate.Interaction{{Repo: "hashicorp/hcl", Role: candidate.Forker}},
+	}
+	r := db.Track(found, first)
+	if r.Status != candidate.New || found.Status != candidate.New {
+		t.Errorf("Track() of a new candidate gave status %q", r.Status)
+	}
+
+	r.SetStatus(candidate.Contacted, first.Add(time.Hour))
+	r.AddNote("said maybe next year", first.Add(2*time.Hour))
+	r.Tag("backend", first.Add(2*time.Hour))
+	r.Tag("backend", first.Add(3*time.Hour))
+	if err = s.SaveCandidates(db); err != nil {
+		t.Fatal(err)
+	}
+	if db, err = s.LoadCandidates(); err != nil {
+		t.Fatal(err)
+	}
+
+	renamed := &candidate.Candidate{
+		User:         fetch.User{ID: "MDQ6VXNlcjE=", Login: "someone-else", Company: githubv4.String("ACME")},
+		Interactions: []candidate.Interaction{{Repo: "zclconf/go-cty", Role: candidate.Reviewer}},
+	}
+	r = db.Track(renamed, first.AddDate(0, 0, 7))
+	if len(db.Records) != 1 {
+		t.Fatalf("Track() of a renamed candidate added a record: %d records", len(db.Records))
+	}
+	if renamed.Status != candidate.Contacted {
+		t.Errorf("Track() gave status %q, want %q", renamed.Status, candidate.Contacted)
+	}
+	if got := r.Candidate.Repos(); !reflect.DeepEqual(got, []string{"hashicorp/hcl", "zclconf/go-cty"}) {
+		t.Errorf("tracked repos = %v", got)
+	}
+	if r.Candidate.User.Company != "ACME" || !r.FirstSeen.Equal(first) {
+		t.Errorf("Track() didn't refresh the snapshot: %+v", r)
+	}
+
+	var kinds []candidate.EventKind
+	for _, e := range r.History {
+		kinds = append(kinds, e.Kind)
+	}
+	want := []candidate.EventKind{candidate.EventFound, candidate.EventStatus, candidate.EventNote, candidate.EventTag}
+	if !reflect.DeepEqual(kinds, want) {
+		t.Errorf("history = %v\nwant %v", kinds, want)
+	}
+
+	if _, err = candidate.ParseStatus("ghosted"); err == nil {
+		t.Errorf("ParseStatus() accepted an unknown status")
+	}
+	if !db.Remove("someone-else") || len(db.Records) != 0 {
+		t.Errorf("Remove() left %d records", len(db.Records))
+	}
+}
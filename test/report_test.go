@@ -0,0 +1,120 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/fetch"
+	"github.com/florinutz/gh-recruiter/report"
+	"github.com/florinutz/gh-recruiter/state"
+	"github.com/shurcooL/githubv4"
+)
+
+func TestReport(t *testing.T) {
+	started := time.Date(2018, 11, 20, 9, 0, 0, 0, time.UTC)
+	run := state.NewRun("repo", []string{"hashicorp", "hcl"}, nil, started)
+	run.AddRepo("hashicorp/hcl")
+	run.Finished = started.Add(90 * time.Second)
+	run.Stats = fetch.Stats{Queries: 12, CacheHits: 3, Cost: 42}
+
+	candidates := []*candidate.Candidate{
+		{
+			User:         fetch.User{Login: "stargazer", Location: "Berlin"},
+			Interactions: []candidate.Interaction{{Repo: "hashicorp/hcl", Role: candidate.Stargazer}},
+		},
+		{
+			User: fetch.User{Login: "reviewer", Name: "<script>alert(1)</script>", Location: "berlin",
+				Company: "A | B"},
+			Interactions: []candidate.Interaction{
+				{Repo: "hashicorp/hcl", Role: candidate.Reviewer, URL: "https://github.com/hashicorp/hcl/pull/7#pullrequestreview-1"},
+				{Repo: "hashicorp/hcl", Role: candidate.Committer, URL: "https://github.com/hashicorp/hcl/commit/abc"},
+			},
+		},
+	}
+	r := report.New(run, candidates, started)
+	if r.Candidates[0].Login() != "reviewer" || r.Candidates[0].Rank != 1 {
+		t.Errorf("the best scored candidate is %s, ranked %d", r.Candidates[0].Login(), r.Candidates[0].Rank)
+	}
+	if want := []report.Bar{{Label: "berlin", Count: 2}}; !reflect.DeepEqual(r.Locations, want) {
+		t.Errorf("Locations = %v\nwant %v", r.Locations, want)
+	}
+
+	tests := []struct {
+		format string
+		want   []string
+		unwant []string
+	}{
+		{
+			format: report.FormatHTML,
+			want: []string{"<svg", `aria-label="Candidates by location"`, "1m30s", ">42<",
+				`href="#candidate-reviewer"`, `id="candidate-reviewer"`, ">hashicorp/hcl#7<",
+				"&lt;script&gt;alert(1)&lt;/script&gt;"},
+			unwant: []string{"<script>"},
+		},
+		{
+			format: report.FormatMarkdown,
+			want: []string{"| API cost (rate limit points) | 42 |", "[hashicorp/hcl](https://github.com/hashicorp/hcl)",
+				"| 1 | [reviewer](#candidate-reviewer)", `A \| B`, "[hashicorp/hcl#7](https://github.com/hashicorp/hcl/pull/7#pullrequestreview-1)"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := r.Write(&b, test.format); err != nil {
+				t.Fatalf("Write()\nerror: %v", err)
+			}
+			for _, want := range test.want {
+				if !strings.Contains(b.String(), want) {
+					t.Errorf("Write() lacks %q:\n%s", want, b.String())
+				}
+			}
+			for _, unwant := range test.unwant {
+				if strings.Contains(b.String(), unwant) {
+					t.Errorf("Write() has %q", unwant)
+				}
+			}
+		})
+	}
+
+	if err := r.Write(&bytes.Buffer{}, "pdf"); err == nil {
+		t.Error("Write() in an unknown format returned no error")
+	}
+}
+
+func TestDistribution(t *testing.T) {
+	got := report.Distribution([]string{"Go", "go", "Rust", "", "C", "Zig", "Rust", "Go"}, 3)
+	want := []report.Bar{{Label: "Go", Count: 3}, {Label: "Rust", Count: 2}, {Label: "other", Count: 3}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Distribution() = %v\nwant %v", got, want)
+	}
+}
+
+func TestStats(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"user": {"login": "someone"}, "rateLimit": {"cost": 3, "remaining": 4990}}}`))
+	}))
+	defer srv.Close()
+
+	stats := &fetch.Stats{}
+	f := fetch.GithubFetcher{Client: githubv4.NewEnterpriseClient(srv.URL, srv.Client()), Stats: stats}
+	for i := 0; i < 2; i++ {
+		if _, err := f.GetUser(context.Background(), "someone"); err != nil {
+			t.Fatalf("GetUser()\nerror: %v", err)
+		}
+	}
+	if got := stats.Snapshot(); got.Queries != 2 || got.Cost != 6 || got.CacheHits != 0 {
+		t.Errorf("Snapshot() = %+v", got)
+	}
+	stats.Reset()
+	if got := stats.Snapshot(); got != (fetch.Stats{}) {
+		t.Errorf("Snapshot() after Reset() = %+v", got)
+	}
+}
@@ -0,0 +1,43 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/schedule"
+)
+
+func TestCron_Next(t *testing.T) {
+	// a tuesday
+	from := time.Date(2018, 11, 20, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2018, 11, 20, 10, 45, 0, 0, time.UTC)},
+		{"0 6 * * 1", time.Date(2018, 11, 26, 6, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2018, 11, 21, 0, 0, 0, 0, time.UTC)},
+		{"30 9-17/4 * * 1-5", time.Date(2018, 11, 20, 13, 30, 0, 0, time.UTC)},
+		{"0 0 1,15 * 7", time.Date(2018, 11, 25, 0, 0, 0, 0, time.UTC)},
+		{"0 12 29 2 *", time.Date(2020, 2, 29, 12, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			c, err := schedule.ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCron()\nerror: %v", err)
+			}
+			if got := c.Next(from); !got.Equal(tt.want) {
+				t.Errorf("Next() = %v\nwant %v", got, tt.want)
+			}
+		})
+	}
+
+	for _, invalid := range []string{"* * * *", "60 * * * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		if _, err := schedule.ParseCron(invalid); err == nil {
+			t.Errorf("ParseCron(%q) didn't fail", invalid)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/fetch"
+	"github.com/shurcooL/githubv4"
+)
+
+func TestUserSearch_Query(t *testing.T) {
+	day := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+
+	tests := []struct {
+		name   string
+		search fetch.UserSearch
+		want   string
+	}{
+		{"empty", fetch.UserSearch{}, "type:user"},
+		{
+			"everything",
+			fetch.UserSearch{
+				Location:    "Hamburg",
+				Language:    "go",
+				Followers:   ">50",
+				Repos:       "5..20",
+				CreatedFrom: day("2012-01-01"),
+				CreatedTo:   day("2014-06-30"),
+			},
+			"type:user location:Hamburg language:go followers:>50 repos:5..20 created:2012-01-01..2014-06-30",
+		},
+		{"quoted location", fetch.UserSearch{Location: "San Francisco"}, `type:user location:"San Francisco"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.search.Query(); got != tt.want {
+				t.Errorf("UserSearch.Query() = %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserSearch_Split(t *testing.T) {
+	from := time.Date(2012, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := fetch.UserSearch{Location: "Berlin", CreatedFrom: from, CreatedTo: from.AddDate(0, 0, 10)}
+
+	left, right, ok := s.Split()
+	if !ok {
+		t.Fatal("a ten day range should split")
+	}
+	if !left.CreatedFrom.Equal(from) || !left.CreatedTo.Equal(from.AddDate(0, 0, 5)) ||
+		!right.CreatedFrom.Equal(from.AddDate(0, 0, 6)) || !right.CreatedTo.Equal(s.CreatedTo) {
+		t.Errorf("Split() = %v..%v, %v..%v", left.CreatedFrom, left.CreatedTo, right.CreatedFrom, right.CreatedTo)
+	}
+	if left.Location != "Berlin" || right.Location != "Berlin" {
+		t.Error("Split() lost the other criteria")
+	}
+
+	if _, _, ok = (fetch.UserSearch{CreatedFrom: from, CreatedTo: from}).Split(); ok {
+		t.Error("a single day range shouldn't split")
+	}
+}
+
+func TestRepoSearch_Query(t *testing.T) {
+	s := fetch.RepoSearch{Topics: []string{"terraform", "hcl"}, Language: "go", MinStars: 200}
+	if want := "topic:terraform topic:hcl language:go stars:>=200 archived:false"; s.Query() != want {
+		t.Errorf("RepoSearch.Query() = %s\nwant %s", s.Query(), want)
+	}
+}
+
+func TestRankRepos(t *testing.T) {
+	now := time.Now()
+	repo := func(name string, stars int, pushed time.Time) fetch.Repo {
+		r := fetch.Repo{Name: githubv4.String(name)}
+		r.Stargazers.TotalCount = githubv4.Int(stars)
+		r.PushedAt = githubv4.DateTime{Time: pushed}
+		return r
+	}
+
+	repos := []fetch.Repo{
+		repo("abandoned", 1000, now.AddDate(-5, 0, 0)),
+		repo("small", 100, now),
+		repo("active", 500, now.AddDate(0, -1, 0)),
+	}
+	fetch.RankRepos(repos, now)
+
+	var got []string
+	for _, r := range repos {
+		got = append(got, string(r.Name))
+	}
+	if want := []string{"active", "abandoned", "small"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RankRepos() = %v\nwant %v", got, want)
+	}
+}
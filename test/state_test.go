@@ -0,0 +1,89 @@
+package test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/state"
+)
+
+func TestStore_Checkpoint(t *testing.T) {
+	s, err := state.NewStoreAt(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fresh, err := s.LoadCheckpoint("hashicorp", "hcl", "forkers")
+	if err != nil || fresh.EndCursor != "" || fresh.Listed {
+		t.Fatalf("LoadCheckpoint() with nothing stored = %+v, error %v", fresh, err)
+	}
+
+	fresh.EndCursor = "Y3Vyc29y"
+	fresh.Logins["forkers"] = []string{"one", "two", "three"}
+	fresh.Logins["reviewers"] = []string{"two", "two"}
+	fresh.Resolve("forker", "two")
+	fresh.Interactions = []state.Interaction{
+		{Login: "two", Role: candidate.Reviewer, URL: "https://github.com/hashicorp/hcl/pull/1#pullrequestreview-1", Author: "one"},
+	}
+	if err = s.SaveCheckpoint(fresh); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.LoadCheckpoint("hashicorp", "hcl", "forkers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.EndCursor != fresh.EndCursor {
+		t.Errorf("EndCursor = %s\nwant %s", got.EndCursor, fresh.EndCursor)
+	}
+	if !reflect.DeepEqual(got.Interactions, fresh.Interactions) {
+		t.Errorf("Interactions = %+v\nwant %+v", got.Interactions, fresh.Interactions)
+	}
+	if unresolved := got.Unresolved("forker", got.Logins["forkers"]); !reflect.DeepEqual(unresolved, []string{"one", "three"}) {
+		t.Errorf("Unresolved() = %v", unresolved)
+	}
+	// resolving a login in a role leaves it unresolved in the others
+	if unresolved := got.Unresolved("reviewer", got.Logins["reviewers"]); !reflect.DeepEqual(unresolved, []string{"two"}) {
+		t.Errorf("Unresolved() for another role = %v", unresolved)
+	}
+
+	if err = s.Delete(got.Key()); err != nil {
+		t.Fatal(err)
+	}
+	if found, _ := s.Load(got.Key(), &state.Checkpoint{}); found {
+		t.Error("checkpoint still there after Delete()")
+	}
+}
+
+func TestStore_Marks(t *testing.T) {
+	s, err := state.NewStoreAt(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := s.LoadMarks("openzipkin", "zipkin-go")
+	if err != nil || !m.LastRun.IsZero() || !m.PRUpdatedAt.IsZero() {
+		t.Fatalf("LoadMarks() for a repo never crawled = %+v, error %v", m, err)
+	}
+
+	newer := time.Date(2018, 11, 20, 10, 0, 0, 0, time.UTC)
+	state.Advance(&m.PRUpdatedAt, newer)
+	state.Advance(&m.PRUpdatedAt, newer.Add(-time.Hour))
+	m.Seen["someone"] = true
+	if err = s.SaveMarks(m); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.LoadMarks("openzipkin", "zipkin-go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.PRUpdatedAt.Equal(newer) {
+		t.Errorf("PRUpdatedAt = %v\nwant %v", got.PRUpdatedAt, newer)
+	}
+	if !got.Seen["someone"] || got.LastRun.IsZero() {
+		t.Errorf("LoadMarks() = %+v", got)
+	}
+}
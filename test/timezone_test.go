@@ -0,0 +1,74 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/timezone"
+)
+
+// commitsAt returns n timestamps a day apart, cycling through the given hours of the location
+func commitsAt(loc *time.Location, n int, hours ...int) (times []time.Time) {
+	start := time.Date(2018, 10, 1, 0, 0, 0, 0, loc)
+	for i := 0; i < n; i++ {
+		times = append(times, start.AddDate(0, 0, i).Add(time.Duration(hours[i%len(hours)])*time.Hour))
+	}
+
+	return
+}
+
+func TestInfer(t *testing.T) {
+	berlin := time.FixedZone("CEST", 2*3600)
+	newYork := time.FixedZone("EDT", -4*3600)
+
+	tests := []struct {
+		name            string
+		times           []time.Time
+		wantOffset      int
+		wantFromOffsets bool
+		minConfidence   float64
+		maxConfidence   float64
+	}{
+		{"offsets recorded", commitsAt(berlin, 30, 10, 23, 3), 2, true, 0.99, 1},
+		{"few samples", commitsAt(berlin, 3, 10), 2, true, 0.09, 0.11},
+		{"utc, berlin office hours", utc(commitsAt(berlin, 40, 9, 11, 14, 16, 18)), 2, false, 0.99, 1},
+		{"utc, new york office hours", utc(commitsAt(newYork, 40, 9, 11, 14, 16, 18)), -4, false, 0.99, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := timezone.Infer(tt.times)
+			if !ok {
+				t.Fatal("Infer() found nothing")
+			}
+			if got.Offset != tt.wantOffset || got.FromOffsets != tt.wantFromOffsets {
+				t.Errorf("Infer() = %+v, want offset %d", got, tt.wantOffset)
+			}
+			if got.Confidence < tt.minConfidence || got.Confidence > tt.maxConfidence {
+				t.Errorf("Infer() confidence = %f, want between %f and %f", got.Confidence, tt.minConfidence,
+					tt.maxConfidence)
+			}
+		})
+	}
+
+	if uniform, _ := timezone.Infer(commitsAt(time.UTC, 48, 0, 3, 6, 9, 12, 15, 18, 21)); uniform.Confidence > 0.01 {
+		t.Errorf("Infer() on commits around the clock = %+v, shouldn't be confident", uniform)
+	}
+	if _, ok := timezone.Infer(nil); ok {
+		t.Error("Infer() without timestamps should find nothing")
+	}
+}
+
+func utc(times []time.Time) []time.Time {
+	for i := range times {
+		times[i] = times[i].UTC()
+	}
+
+	return times
+}
+
+func TestEstimate_String(t *testing.T) {
+	if got := (timezone.Estimate{Offset: -4, Confidence: 0.756}).String(); got != "UTC-04:00 (76%)" {
+		t.Errorf("String() = %s", got)
+	}
+}
@@ -0,0 +1,110 @@
+package test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+	"github.com/florinutz/gh-recruiter/fetch"
+	"github.com/florinutz/gh-recruiter/xlsx"
+)
+
+func TestCandidatesWorkbook(t *testing.T) {
+	candidates := []*candidate.Candidate{
+		{
+			User: fetch.User{Login: "forker", Name: "Fork & Co <3>"},
+			Interactions: []candidate.Interaction{
+				{Repo: "hashicorp/hcl", Role: candidate.Forker},
+			},
+		},
+		{
+			User: fetch.User{Login: "reviewer", Location: "Hamburg"},
+			Interactions: []candidate.Interaction{
+				{Repo: "hashicorp/hcl", Role: candidate.Reviewer, URL: "https://github.com/hashicorp/hcl/pull/7#pullrequestreview-1"},
+				{Repo: "hashicorp/hcl", Role: candidate.Commenter, URL: "https://github.com/hashicorp/hcl/pull/8#issuecomment-2"},
+			},
+		},
+	}
+
+	var b bytes.Buffer
+	if err := xlsx.Candidates(candidates, time.Now()).Write(&b); err != nil {
+		t.Fatalf("Write()\nerror: %v", err)
+	}
+	z, err := zip.NewReader(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if err != nil {
+		t.Fatalf("the workbook isn't a zip\nerror: %v", err)
+	}
+
+	parts := map[string]string{}
+	for _, f := range z.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := ioutil.ReadAll(r)
+		r.Close()
+		parts[f.Name] = string(content)
+
+		// every part has to be well formed
+		d := xml.NewDecoder(bytes.NewReader(content))
+		for {
+			if _, err = d.Token(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("%s is malformed\nerror: %v", f.Name, err)
+			}
+		}
+	}
+
+	tests := []struct {
+		part string
+		want []string
+	}{
+		{"[Content_Types].xml", []string{"/xl/worksheets/sheet6.xml"}},
+		{"xl/workbook.xml", []string{`name="Candidates"`, `name="Forkers"`, `name="Stargazers"`}},
+		{"xl/worksheets/sheet1.xml", []string{">Login<", ">reviewer<", "Fork &amp; Co &lt;3&gt;", "<hyperlinks>"}},
+		{"xl/worksheets/sheet3.xml", []string{"<sheetData>", "</sheetData>"}},
+		{"xl/worksheets/sheet4.xml", []string{">hashicorp/hcl#7<", ">Hamburg<"}},
+		{"xl/worksheets/_rels/sheet4.xml.rels", []string{`Target="https://github.com/reviewer"`,
+			`Target="https://github.com/hashicorp/hcl/pull/7#pullrequestreview-1"`}},
+	}
+	for _, test := range tests {
+		content, ok := parts[test.part]
+		if !ok {
+			t.Errorf("the workbook lacks %s", test.part)
+			continue
+		}
+		for _, want := range test.want {
+			if !strings.Contains(content, want) {
+				t.Errorf("%s lacks %q:\n%s", test.part, want, content)
+			}
+		}
+	}
+	if _, ok := parts["xl/worksheets/_rels/sheet6.xml.rels"]; ok {
+		t.Error("the empty stargazers sheet has links")
+	}
+}
+
+func TestCellRef(t *testing.T) {
+	tests := []struct {
+		col, row int
+		want     string
+	}{
+		{0, 0, "A1"},
+		{25, 9, "Z10"},
+		{26, 0, "AA1"},
+		{701, 1, "ZZ2"},
+		{702, 2, "AAA3"},
+	}
+	for _, test := range tests {
+		if got := xlsx.CellRef(test.col, test.row); got != test.want {
+			t.Errorf("CellRef(%d, %d) = %s\nwant %s", test.col, test.row, got, test.want)
+		}
+	}
+}
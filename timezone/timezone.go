@@ -0,0 +1,148 @@
+package timezone
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// The local hours people are assumed to be active in, [ActiveFrom, ActiveTo)
+const (
+	ActiveFrom = 8
+	ActiveTo   = 20
+)
+
+// FullConfidenceSamples is the number of timestamps from which the sample size doesn't lower the confidence
+const FullConfidenceSamples = 30
+
+// Estimate is a guess of someone's UTC offset
+type Estimate struct {
+	// Offset is the estimated UTC offset, in hours
+	Offset int
+	// Confidence is between 0 and 1
+	Confidence float64
+	// Samples is the number of timestamps the estimate is based on
+	Samples int
+	// FromOffsets is set when the estimate comes from offsets the timestamps carried,
+	// as opposed to the spread of their hours
+	FromOffsets bool
+}
+
+// String returns the estimate as in "UTC+02:00 (80%)"
+func (e Estimate) String() string {
+	sign := "+"
+	offset := e.Offset
+	if offset < 0 {
+		sign, offset = "-", -offset
+	}
+
+	return fmt.Sprintf("UTC%s%02d:00 (%.0f%%)", sign, offset, e.Confidence*100)
+}
+
+// Infer estimates the UTC offset of the author of the given timestamps (e.g. commit authored dates).
+// When the timestamps carry non UTC offsets, which git records from the author's machine, the most
+// common one wins. Otherwise the offset that puts most of the timestamps within the active hours does.
+// It returns false when there are no timestamps.
+func Infer(times []time.Time) (Estimate, bool) {
+	if len(times) == 0 {
+		return Estimate{}, false
+	}
+
+	if e, ok := fromOffsets(times); ok {
+		return e, true
+	}
+
+	return fromHours(times), true
+}
+
+func sampleFactor(samples int) float64 {
+	if samples >= FullConfidenceSamples {
+		return 1
+	}
+
+	return float64(samples) / FullConfidenceSamples
+}
+
+func fromOffsets(times []time.Time) (Estimate, bool) {
+	counts := map[int]int{}
+	nonUTC := false
+	for _, t := range times {
+		_, seconds := t.Zone()
+		if seconds != 0 {
+			nonUTC = true
+		}
+		counts[seconds/3600]++
+	}
+	if !nonUTC {
+		return Estimate{}, false // probably normalized to UTC by whoever served them
+	}
+
+	best, bestCount := 0, -1
+	for offset, count := range counts {
+		if count > bestCount || (count == bestCount && offset < best) {
+			best, bestCount = offset, count
+		}
+	}
+
+	return Estimate{
+		Offset:      best,
+		Confidence:  float64(bestCount) / float64(len(times)) * sampleFactor(len(times)),
+		Samples:     len(times),
+		FromOffsets: true,
+	}, true
+}
+
+func fromHours(times []time.Time) Estimate {
+	var hours [24]int
+	for _, t := range times {
+		hours[t.UTC().Hour()]++
+	}
+
+	// the circular mean of the hours, in order to center the active hours on it when offsets tie
+	var x, y float64
+	for hour, count := range hours {
+		angle := float64(hour) / 24 * 2 * math.Pi
+		x += float64(count) * math.Cos(angle)
+		y += float64(count) * math.Sin(angle)
+	}
+	meanHour := math.Atan2(y, x) / (2 * math.Pi) * 24
+
+	best, bestActive, bestDistance := 0, -1, 0.0
+	for offset := -12; offset <= 14; offset++ {
+		active := 0
+		for utcHour, count := range hours {
+			local := ((utcHour+offset)%24 + 24) % 24
+			if local >= ActiveFrom && local < ActiveTo {
+				active += count
+			}
+		}
+		distance := hourDistance(meanHour+float64(offset), float64(ActiveFrom+ActiveTo)/2)
+		if active > bestActive || (active == bestActive && distance < bestDistance) {
+			best, bestActive, bestDistance = offset, active, distance
+		}
+	}
+
+	// with half the day counted as active, even a uniform spread has a best offset holding half
+	// the timestamps, so only what goes above that is telling
+	share := float64(bestActive) / float64(len(times))
+	confidence := (share - 0.5) * 2
+	if confidence < 0 {
+		confidence = 0
+	}
+
+	return Estimate{
+		Offset:     best,
+		Confidence: confidence * sampleFactor(len(times)),
+		Samples:    len(times),
+	}
+}
+
+// hourDistance is the distance between two hours of the day, going around midnight if shorter
+func hourDistance(a, b float64) float64 {
+	d := math.Mod(math.Abs(a-b), 24)
+	if d > 12 {
+		d = 24 - d
+	}
+
+	return d
+}
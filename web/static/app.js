@@ -0,0 +1,194 @@
+'use strict';
+
+const statuses = ['new', 'contacted', 'replied', 'interviewing', 'rejected', 'hired'];
+const $ = (id) => document.getElementById(id);
+
+let apiKey = localStorage.getItem('apiKey') || '';
+let selected = null;
+
+async function api(method, path, body) {
+  const resp = await fetch(path, {
+    method,
+    headers: {'X-API-Key': apiKey, 'Content-Type': 'application/json'},
+    body: body === undefined ? undefined : JSON.stringify(body),
+  });
+  if (resp.status === 401) {
+    signOut();
+    throw new Error('invalid api key');
+  }
+  if (!resp.ok) {
+    const payload = await resp.json().catch(() => ({}));
+    throw new Error(payload.error || resp.statusText);
+  }
+  return resp.status === 204 ? null : resp.json();
+}
+
+function showError(err) {
+  $('error').textContent = err.message;
+  $('error').hidden = false;
+  setTimeout(() => { $('error').hidden = true; }, 5000);
+}
+
+function el(tag, text, attrs) {
+  const node = document.createElement(tag);
+  if (text !== undefined) node.textContent = text;
+  Object.assign(node, attrs || {});
+  return node;
+}
+
+function summary(interactions) {
+  const roles = {};
+  (interactions || []).forEach((i) => {
+    roles[i.Repo] = roles[i.Repo] || new Set();
+    roles[i.Repo].add(i.Role);
+  });
+  return Object.keys(roles).sort().map((repo) => `${repo}: ${[...roles[repo]].sort().join(', ')}`).join('; ');
+}
+
+async function loadList() {
+  const params = new URLSearchParams();
+  new FormData($('filters')).forEach((value, key) => { if (value) params.set(key, value); });
+
+  let candidates;
+  try {
+    candidates = await api('GET', `/api/candidates?${params}`);
+  } catch (err) {
+    showError(err);
+    return;
+  }
+
+  const rows = $('rows');
+  rows.replaceChildren();
+  candidates.forEach((c) => {
+    const user = c.Candidate.User;
+    const status = el('td');
+    status.appendChild(el('span', c.Status, {className: `status status-${c.Status}`}));
+    const row = el('tr');
+    row.append(
+      el('td', c.Login),
+      el('td', user.Name),
+      el('td', user.Location),
+      el('td', c.Score.toFixed(2)),
+      status,
+      el('td', summary(c.Candidate.Interactions)),
+    );
+    row.classList.toggle('selected', c.Login === selected);
+    row.addEventListener('click', () => showCard(c.Login));
+    rows.appendChild(row);
+  });
+  $('count').textContent = `${candidates.length} candidates`;
+}
+
+async function showCard(login) {
+  let c;
+  try {
+    c = await api('GET', `/api/candidates/${encodeURIComponent(login)}`);
+  } catch (err) {
+    showError(err);
+    return;
+  }
+  selected = c.Login;
+
+  const user = c.Candidate.User;
+  $('avatar').src = `https://github.com/${encodeURIComponent(c.Login)}.png?size=192`;
+  $('name').textContent = user.Name || c.Login;
+  $('login').textContent = `@${c.Login}`;
+  $('login').href = `https://github.com/${encodeURIComponent(c.Login)}`;
+  $('location').textContent = user.Location;
+  $('company').textContent = user.Company;
+  $('bio').textContent = user.Bio;
+  $('score').textContent = c.Score.toFixed(2);
+  $('languages').textContent = (c.Candidate.Languages || []).slice(0, 5)
+    .map((l) => `${l.Name} ${Math.round(l.Share * 100)}%`).join(', ') || 'unknown';
+  $('tags').textContent = (c.Tags || []).join(' ');
+  $('email').textContent = user.Email;
+  $('status').value = c.Status;
+
+  const interactions = $('interactions');
+  interactions.replaceChildren();
+  (c.Candidate.Interactions || []).forEach((i) => {
+    const item = el('li');
+    const href = i.URL || `https://github.com/${i.Repo}`;
+    item.append(`${i.Role} in `, el('a', i.URL ? i.Repo + ' ↗' : i.Repo, {href, target: '_blank', rel: 'noopener'}));
+    interactions.appendChild(item);
+  });
+
+  const history = $('history');
+  history.replaceChildren();
+  (c.History || []).slice().reverse().forEach((e) => {
+    history.appendChild(el('li', `${new Date(e.At).toLocaleString()}: ${e.Kind} ${e.Value}`));
+  });
+
+  $('card').hidden = false;
+  loadList();
+}
+
+function signOut() {
+  apiKey = '';
+  localStorage.removeItem('apiKey');
+  $('app').hidden = true;
+  $('sign-out').hidden = true;
+  $('key-form').hidden = false;
+}
+
+function signIn() {
+  $('key-form').hidden = true;
+  $('sign-out').hidden = false;
+  $('app').hidden = false;
+  loadList();
+}
+
+function init() {
+  statuses.forEach((s) => {
+    $('status').appendChild(el('option', s));
+    $('filters').elements.status.appendChild(el('option', s));
+  });
+
+  $('key-form').addEventListener('submit', (event) => {
+    event.preventDefault();
+    apiKey = $('key').value;
+    localStorage.setItem('apiKey', apiKey);
+    signIn();
+  });
+  $('sign-out').addEventListener('click', signOut);
+
+  let debounce;
+  $('filters').addEventListener('input', () => {
+    clearTimeout(debounce);
+    debounce = setTimeout(loadList, 250);
+  });
+  $('filters').addEventListener('submit', (event) => event.preventDefault());
+
+  $('close').addEventListener('click', () => {
+    $('card').hidden = true;
+    selected = null;
+    loadList();
+  });
+
+  $('status-form').addEventListener('submit', async (event) => {
+    event.preventDefault();
+    try {
+      await api('POST', `/api/candidates/${encodeURIComponent(selected)}/status`, {status: $('status').value});
+      showCard(selected);
+    } catch (err) {
+      showError(err);
+    }
+  });
+
+  $('dnc').addEventListener('click', async () => {
+    const reason = prompt(`Why shouldn't ${selected} be contacted?`);
+    if (reason === null) return;
+    try {
+      await api('POST', `/api/candidates/${encodeURIComponent(selected)}/dnc`, {reason});
+      $('card').hidden = true;
+      selected = null;
+      loadList();
+    } catch (err) {
+      showError(err);
+    }
+  });
+
+  if (apiKey) signIn(); else signOut();
+}
+
+init();
@@ -0,0 +1,81 @@
+package xlsx
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/florinutz/gh-recruiter/candidate"
+)
+
+// SummaryHeader is the header of the summary sheet
+var SummaryHeader = []string{"Login", "Name", "Email", "Location", "Company", "Score", "Roles", "Repos",
+	"Top languages", "Hireable", "Status"}
+
+// RoleHeader is the header of the per role sheets
+var RoleHeader = []string{"Login", "Name", "Location", "Score", "Repo", "Link"}
+
+// Candidates returns a workbook with a summary sheet having a row per candidate, the best scored first,
+// followed by a sheet per role having a row per interaction
+func Candidates(candidates []*candidate.Candidate, now time.Time) *Workbook {
+	ranked := append([]*candidate.Candidate{}, candidates...)
+	scores := make(map[*candidate.Candidate]float64, len(ranked))
+	for _, c := range ranked {
+		scores[c] = c.Score(now)
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return scores[ranked[i]] > scores[ranked[j]] })
+
+	wb := &Workbook{}
+	summary := wb.Sheet("Candidates", SummaryHeader...)
+	for _, c := range ranked {
+		var roles []string
+		for _, role := range c.Roles("") {
+			roles = append(roles, string(role))
+		}
+		summary.Add(
+			profile(c),
+			Text(string(c.User.Name)),
+			Text(string(c.User.Email)),
+			Text(string(c.User.Location)),
+			Text(string(c.User.Company)),
+			Number(round(scores[c])),
+			Text(strings.Join(roles, ", ")),
+			Text(strings.Join(c.Repos(), ", ")),
+			Text(strings.Join(c.Languages.Top(candidate.TopLanguagesCount), " ")),
+			Text(strconv.FormatBool(bool(c.User.IsHireable))),
+			Text(string(c.Status)),
+		)
+	}
+
+	for _, role := range candidate.Roles {
+		sheet := wb.Sheet(strings.Title(string(role))+"s", RoleHeader...)
+		for _, c := range ranked {
+			for _, i := range c.Interactions {
+				if i.Role != role {
+					continue
+				}
+				sheet.Add(
+					profile(c),
+					Text(string(c.User.Name)),
+					Text(string(c.User.Location)),
+					Number(round(scores[c])),
+					Link(i.Repo, "https://github.com/"+i.Repo),
+					Link(i.Label(), i.URL),
+				)
+			}
+		}
+	}
+
+	return wb
+}
+
+// profile links the candidate's login to their github profile
+func profile(c *candidate.Candidate) Cell {
+	return Link(c.Login(), "https://github.com/"+c.Login())
+}
+
+func round(f float64) float64 {
+	f, _ = strconv.ParseFloat(strconv.FormatFloat(f, 'f', 2, 64), 64)
+	return f
+}
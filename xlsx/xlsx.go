@@ -0,0 +1,340 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// The cell styles, indexes into styles.xml's cellXfs
+const (
+	styleDefault = iota
+	styleHeader
+	styleLink
+)
+
+// maxCellLength is the longest text a cell holds
+const maxCellLength = 32767
+
+// maxSheetName is the longest name a sheet can have
+const maxSheetName = 31
+
+// Cell is a worksheet cell
+type Cell struct {
+	text    string
+	number  float64
+	numeric bool
+	link    string
+}
+
+// Text returns a text cell
+func Text(s string) Cell {
+	return Cell{text: s}
+}
+
+// Number returns a numeric cell
+func Number(f float64) Cell {
+	return Cell{number: f, numeric: true}
+}
+
+// Link returns a text cell linking to the url. Without an url it's a plain text cell.
+func Link(text, url string) Cell {
+	if text == "" {
+		text = url
+	}
+
+	return Cell{text: text, link: url}
+}
+
+// Sheet is a worksheet whose first row is its header
+type Sheet struct {
+	Name   string
+	Header []string
+	Rows   [][]Cell
+}
+
+// Add appends a row to the sheet
+func (s *Sheet) Add(row ...Cell) {
+	s.Rows = append(s.Rows, row)
+}
+
+// Workbook holds worksheets
+type Workbook struct {
+	Sheets []*Sheet
+}
+
+// Sheet adds a worksheet to the workbook
+func (wb *Workbook) Sheet(name string, header ...string) *Sheet {
+	s := &Sheet{Name: name, Header: header}
+	wb.Sheets = append(wb.Sheets, s)
+
+	return s
+}
+
+// part is one of the files a workbook is zipped from
+type part struct {
+	name    string
+	content []byte
+}
+
+// Write writes the workbook as an Office Open XML spreadsheet
+func (wb *Workbook) Write(w io.Writer) error {
+	if len(wb.Sheets) == 0 {
+		return errors.New("a workbook needs at least one sheet")
+	}
+
+	z := zip.NewWriter(w)
+	parts := []part{
+		{"[Content_Types].xml", wb.contentTypes()},
+		{"_rels/.rels", []byte(rootRels)},
+		{"xl/workbook.xml", wb.workbook()},
+		{"xl/_rels/workbook.xml.rels", wb.workbookRels()},
+		{"xl/styles.xml", []byte(styles)},
+	}
+	for i, s := range wb.Sheets {
+		sheet, rels := s.xml()
+		parts = append(parts, part{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), sheet})
+		if rels != nil {
+			parts = append(parts, part{fmt.Sprintf("xl/worksheets/_rels/sheet%d.xml.rels", i+1), rels})
+		}
+	}
+
+	for _, p := range parts {
+		f, err := z.Create(p.name)
+		if err != nil {
+			return errors.Wrap(err, p.name)
+		}
+		if _, err = f.Write(p.content); err != nil {
+			return errors.Wrap(err, p.name)
+		}
+	}
+
+	return z.Close()
+}
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+
+const rootRels = xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+// styles has a default, a bold (header) and a hyperlink style
+const styles = xmlHeader + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<fonts count="3">` +
+	`<font><sz val="11"/><name val="Calibri"/></font>` +
+	`<font><b/><sz val="11"/><name val="Calibri"/></font>` +
+	`<font><u/><sz val="11"/><color rgb="FF0563C1"/><name val="Calibri"/></font>` +
+	`</fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="3">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`<xf numFmtId="0" fontId="2" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`</cellXfs>` +
+	`</styleSheet>`
+
+func (wb *Workbook) contentTypes() []byte {
+	var b bytes.Buffer
+	b.WriteString(xmlHeader + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := range wb.Sheets {
+		fmt.Fprintf(&b, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+	}
+	b.WriteString(`</Types>`)
+
+	return b.Bytes()
+}
+
+func (wb *Workbook) workbook() []byte {
+	var b bytes.Buffer
+	b.WriteString(xmlHeader + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	used := map[string]bool{}
+	for i, s := range wb.Sheets {
+		fmt.Fprintf(&b, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escape(sheetName(s.Name, i, used)), i+1, i+1)
+	}
+	b.WriteString(`</sheets></workbook>`)
+
+	return b.Bytes()
+}
+
+func (wb *Workbook) workbookRels() []byte {
+	var b bytes.Buffer
+	b.WriteString(xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := range wb.Sheets {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+	fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(wb.Sheets)+1)
+	b.WriteString(`</Relationships>`)
+
+	return b.Bytes()
+}
+
+// sheetName makes the name one Excel accepts: short enough, without the forbidden characters and unique
+func sheetName(name string, index int, used map[string]bool) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, strings.Trim(name, "'"))
+	if name == "" {
+		name = "Sheet" + strconv.Itoa(index+1)
+	}
+	name = shorten(name, maxSheetName)
+	for unique, n := name, 2; ; n++ {
+		if !used[strings.ToLower(unique)] {
+			used[strings.ToLower(unique)] = true
+			return unique
+		}
+		suffix := " " + strconv.Itoa(n)
+		unique = shorten(name, maxSheetName-len(suffix)) + suffix
+	}
+}
+
+// shorten keeps the first max runes of s
+func shorten(s string, max int) string {
+	if runes := []rune(s); len(runes) > max {
+		return string(runes[:max])
+	}
+
+	return s
+}
+
+// xml returns the sheet and, when it has links, its relationships
+func (s *Sheet) xml() (sheet, rels []byte) {
+	rows := make([][]Cell, 0, len(s.Rows)+1)
+	if len(s.Header) > 0 {
+		header := make([]Cell, len(s.Header))
+		for i, h := range s.Header {
+			header[i] = Text(h)
+		}
+		rows = append(rows, header)
+	}
+	rows = append(rows, s.Rows...)
+
+	var b bytes.Buffer
+	b.WriteString(xmlHeader + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">`)
+	if len(s.Header) > 0 {
+		b.WriteString(`<sheetViews><sheetView workbookViewId="0">` +
+			`<pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+	}
+	if widths := columnWidths(rows); len(widths) > 0 {
+		b.WriteString(`<cols>`)
+		for i, width := range widths {
+			fmt.Fprintf(&b, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, i+1, i+1, width)
+		}
+		b.WriteString(`</cols>`)
+	}
+
+	var links []string
+	b.WriteString(`<sheetData>`)
+	for r, row := range rows {
+		fmt.Fprintf(&b, `<row r="%d">`, r+1)
+		for c, cell := range row {
+			ref := CellRef(c, r)
+			style := styleDefault
+			if r == 0 && len(s.Header) > 0 {
+				style = styleHeader
+			} else if cell.link != "" {
+				style = styleLink
+				links = append(links, ref, cell.link)
+			}
+			switch {
+			case cell.numeric:
+				fmt.Fprintf(&b, `<c r="%s" s="%d"><v>%s</v></c>`, ref, style,
+					strconv.FormatFloat(cell.number, 'f', -1, 64))
+			case cell.text != "":
+				fmt.Fprintf(&b, `<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, style,
+					escape(truncate(cell.text)))
+			}
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData>`)
+
+	if len(links) > 0 {
+		var r bytes.Buffer
+		r.WriteString(xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+		b.WriteString(`<hyperlinks>`)
+		for i := 0; i < len(links); i += 2 {
+			id := i/2 + 1
+			fmt.Fprintf(&b, `<hyperlink ref="%s" r:id="rId%d"/>`, links[i], id)
+			fmt.Fprintf(&r, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" Target="%s" TargetMode="External"/>`,
+				id, escape(links[i+1]))
+		}
+		b.WriteString(`</hyperlinks>`)
+		r.WriteString(`</Relationships>`)
+		rels = r.Bytes()
+	}
+	b.WriteString(`</worksheet>`)
+
+	return b.Bytes(), rels
+}
+
+// columnWidths sizes the columns to their content, within limits
+func columnWidths(rows [][]Cell) (widths []int) {
+	for _, row := range rows {
+		for c, cell := range row {
+			for len(widths) <= c {
+				widths = append(widths, 8)
+			}
+			n := utf8.RuneCountInString(cell.text)
+			if cell.numeric {
+				n = len(strconv.FormatFloat(cell.number, 'f', -1, 64))
+			}
+			if n+2 > widths[c] {
+				widths[c] = n + 2
+			}
+		}
+	}
+	for c := range widths {
+		if widths[c] > 60 {
+			widths[c] = 60
+		}
+	}
+
+	return
+}
+
+// CellRef returns the A1 style reference of the zero based column and row
+func CellRef(col, row int) string {
+	name := ""
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = string(rune('A'+(col-1)%26)) + name
+	}
+
+	return name + strconv.Itoa(row+1)
+}
+
+func truncate(s string) string {
+	if len(s) <= maxCellLength {
+		return s
+	}
+	s = s[:maxCellLength]
+	for !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+
+	return s
+}
+
+func escape(s string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(s))
+
+	return b.String()
+}